@@ -38,8 +38,7 @@ type cmd struct {
 
 func (c *cmd) Execute(args []string) {
 	if err := c.app.Run(context.Background(), args); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(commands.PrintError(c.app, err))
 	}
 }
 
@@ -81,7 +80,9 @@ func newCmd() *cmd {
 
 	app.Flags = []cli.Flag{
 		debugFlags,
+		commands.OutputFlag,
 	}
+	app.ExitErrHandler = commands.HandleExitError
 	app.Commands = []*cli.Command{
 		commands.Info,
 		commands.Install,
@@ -101,6 +102,15 @@ func newCmd() *cmd {
 		commands.Config,
 		commands.Exec,
 		commands.Cd,
+		commands.Which,
+		commands.Bundle,
+		commands.Verify,
+		commands.Du,
+		commands.Alias,
+		commands.Plugin,
+		commands.Reshim,
+		commands.ShimExec,
+		commands.RefreshCache,
 	}
 
 	return &cmd{app: app, version: version}
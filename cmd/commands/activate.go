@@ -22,18 +22,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"sync"
 	"text/template"
 
-	"github.com/version-fox/vfox/internal"
 	"github.com/version-fox/vfox/internal/pathmeta"
-	"github.com/version-fox/vfox/internal/sdk"
 
 	"github.com/urfave/cli/v3"
 	"github.com/version-fox/vfox/internal/env"
 	"github.com/version-fox/vfox/internal/shared/logger"
 	"github.com/version-fox/vfox/internal/shell"
-	"golang.org/x/sync/errgroup"
 )
 
 var Activate = &cli.Command{
@@ -51,7 +47,7 @@ func activateCmd(ctx context.Context, cmd *cli.Command) error {
 	if env.IsInheritedHookSession() {
 		_ = os.Unsetenv(pathmeta.HookCurTmpPath)
 	}
-	manager, err := internal.NewSdkManager()
+	manager, err := newShellOutputManager()
 	if err != nil {
 		return err
 	}
@@ -65,105 +61,21 @@ func activateCmd(ctx context.Context, cmd *cli.Command) error {
 		return err
 	}
 
-	projectToml, _ := chain.GetTomlByScope(env.Project)
 	// Give a chance to legacy file to set tool versions if not already set in vfox.toml
 	// This allows backward compatibility with older projects using legacy files
 	// New projects should use vfox.toml directly
-	_ = manager.ParseLegacyFile(runtimeEnvContext.CurrentWorkingDir, func(sdkname, version string) {
-		// Set only if not already set in vfox.toml
-		if _, ok := projectToml.GetToolVersion(sdkname); !ok {
-			projectToml.SetTool(sdkname, version)
-		}
-	})
+	applyLegacyFiles(manager, chain)
 
 	// 2. Process each SDK: check if link is needed, create symlinks if necessary
-	// Collect envs by scope to ensure proper PATH priority: Project > Session > Global > System
-	allTools := chain.GetAllTools()
-	envsByScope := map[env.UseScope]*env.Envs{
-		env.Project: env.NewEnvs(),
-		env.Session: env.NewEnvs(),
-		env.Global:  env.NewEnvs(),
-	}
-	var mu sync.Mutex
-
-	// Process SDKs concurrently using errgroup
-	g, _ := errgroup.WithContext(ctx)
-
-	for sdkName := range allTools {
-		sdkName := sdkName // Capture loop variable
-
-		g.Go(func() error {
-			// Lookup SDK
-			sdkObj, err := manager.LookupSdk(sdkName)
-			if err != nil {
-				logger.Debugf("SDK %s not found: %v", sdkName, err)
-				return nil // Continue processing other SDKs
-			}
-
-			// Resolve to the highest-priority installed version across scopes
-			toolConfig, scope, sdkVersion, ok := resolveInstalledToolConfig(chain, sdkObj, sdkName)
-			if !ok {
-				return nil
-			}
-
-			// Determine the actual scope to use
-			// - If the scope is Project but linking is not enabled, downgrade to Session
-			// - Global always uses link (no change)
-			// - Session always uses link (no change)
-			actualScope := scope
-			if env.Project == scope && sdk.IsUseUnLink(toolConfig.Attr) {
-				actualScope = env.Session
-			}
-
-			// Create symlinks if needed (internal logic checks if symlink already exists)
-			if err := sdkObj.CreateSymlinksForScope(sdkVersion, actualScope); err != nil {
-				logger.Debugf("Failed to create symlinks for %s@%s (scope: %s): %v",
-					sdkName, sdkVersion, actualScope.String(), err)
-				return nil // Continue processing other SDKs
-			}
-
-			// Get environment variables pointing to symlinks
-			sdkEnvs, err := sdkObj.EnvKeysForScope(sdkVersion, actualScope)
-			if err != nil {
-				logger.Debugf("Failed to get env keys for %s@%s: %v", sdkName, sdkVersion, err)
-				return nil // Continue processing other SDKs
-			}
-
-			// Collect envs by scope to ensure proper PATH priority (thread-safe)
-			mu.Lock()
-			envsByScope[actualScope].Merge(sdkEnvs)
-			mu.Unlock()
-
-			return nil
-		})
-	}
-
-	// Wait for all SDK processing to complete
-	if err := g.Wait(); err != nil {
+	tools, err := resolveScopedTools(ctx, manager, chain, true)
+	if err != nil {
 		return err
 	}
+	markToolsUsed(manager, tools)
 
-	// 3. Merge envs by scope priority: Project > Session > Global
-	// This ensures proper priority for both PATH (Project first) and Vars (Project overrides)
-	finalEnvs := env.NewEnvs()
-	scopePriority := []env.UseScope{env.Project, env.Session, env.Global}
-	finalEnvs.MergeByScopePriority(envsByScope, scopePriority)
-
-	// 3. Build final PATH with proper priority:
+	// 3. Build final envs with proper priority:
 	// User-injected paths (e.g., virtualenv) > Project > Session > Global > Cleaned System PATH
-	//
-	// SplitSystemPaths separates:
-	// - prefixPaths: paths appearing BEFORE first vfox path (user-injected, highest priority)
-	// - cleanSystemPaths: remaining non-vfox paths (lowest priority)
-	prefixPaths, cleanSystemPaths := runtimeEnvContext.SplitSystemPaths()
-
-	// Build final path order: prefix > vfox > clean system
-	// We need to prepend prefixPaths to maintain their highest priority
-	newPaths := env.NewPaths(env.EmptyPaths)
-	newPaths.Merge(prefixPaths)
-	newPaths.Merge(finalEnvs.Paths)
-	newPaths.Merge(cleanSystemPaths)
-	finalEnvs.Paths = newPaths
+	finalEnvs := buildScopedEnvs(runtimeEnvContext, tools)
 
 	// 4. Export environment variables
 	// Note: This step must be the first.
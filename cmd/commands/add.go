@@ -38,6 +38,11 @@ var Add = &cli.Command{
 			Name:  "alias",
 			Usage: "plugin alias",
 		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "Approve the permissions of the plugin without prompting",
+		},
 	},
 	Action:   addCmd,
 	Category: CategoryPlugin,
@@ -53,11 +58,12 @@ func addCmd(ctx context.Context, cmd *cli.Command) error {
 	}
 	defer manager.Close()
 
+	yes := cmd.Bool("yes")
 	// multiple plugins
 	if args.Len() > 1 {
 		for index, sdkName := range args.Slice() {
 			pterm.Printf("[%s/%d]: Adding %s plugin...\n", pterm.Green(index+1), args.Len(), pterm.Green(sdkName))
-			if err := manager.Add(sdkName, "", ""); err != nil {
+			if err := manager.Add(sdkName, "", "", yes); err != nil {
 				pterm.Println(fmt.Sprintf("Add plugin(%s) failed: %s", pterm.Red(sdkName), err.Error()))
 			}
 		}
@@ -66,7 +72,7 @@ func addCmd(ctx context.Context, cmd *cli.Command) error {
 		sdkName := args.First()
 		source := cmd.String("source")
 		alias := cmd.String("alias")
-		err := manager.Add(sdkName, source, alias)
+		err := manager.Add(sdkName, source, alias, yes)
 		if err == nil {
 			pterm.Printf("Please use `%s` to install the version you need.\n", pterm.LightBlue(fmt.Sprintf("vfox install %s@<version>", sdkName)))
 		}
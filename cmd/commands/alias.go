@@ -0,0 +1,146 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v3"
+	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/sdk"
+)
+
+var Alias = &cli.Command{
+	Name:  "alias",
+	Usage: "Manage named aliases for SDK versions",
+	Commands: []*cli.Command{
+		{
+			Name:      "set",
+			Usage:     "Point an alias to a version",
+			ArgsUsage: "<sdk> <alias> <version>",
+			Action:    aliasSetCmd,
+		},
+		{
+			Name:      "ls",
+			Aliases:   []string{"list"},
+			Usage:     "List the aliases of one or all SDKs",
+			ArgsUsage: "[<sdk>]",
+			Action:    aliasListCmd,
+		},
+		{
+			Name:      "rm",
+			Aliases:   []string{"remove"},
+			Usage:     "Remove an alias",
+			ArgsUsage: "<sdk> <alias>",
+			Action:    aliasRemoveCmd,
+		},
+	},
+	Category: CategorySDK,
+}
+
+// aliasOutput is the schema of an alias in structured output.
+type aliasOutput struct {
+	Sdk     string `json:"sdk" yaml:"sdk"`
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+}
+
+func aliasSetCmd(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 3 {
+		return cli.Exit("usage: vfox alias set <sdk> <alias> <version>", 1)
+	}
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	name := strings.ToLower(cmd.Args().Get(0))
+	alias, version := cmd.Args().Get(1), sdk.Version(cmd.Args().Get(2))
+	source, err := manager.LookupSdk(name)
+	if err != nil {
+		return fmt.Errorf("%s not supported, error: %w", name, err)
+	}
+	name = source.Metadata().Name
+	if err = sdk.SetAlias(manager.RuntimeEnvContext, name, alias, version); err != nil {
+		return err
+	}
+	pterm.Printf("%s now points to %s\n", pterm.LightBlue(name+"@"+alias), pterm.LightGreen(string(version)))
+	if !source.CheckRuntimeExist(version) {
+		pterm.Printf("%s is not installed yet, run `vfox install %s@%s`.\n", name+"@"+string(version), name, alias)
+	}
+	return nil
+}
+
+func aliasListCmd(ctx context.Context, cmd *cli.Command) error {
+	manager, err := newOutputManager(cmd)
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	var names []string
+	if name := cmd.Args().First(); name != "" {
+		names = []string{strings.ToLower(name)}
+	} else if names, err = sdk.AliasedSdks(manager.RuntimeEnvContext); err != nil {
+		return err
+	}
+
+	result := make([]*aliasOutput, 0)
+	for _, name := range names {
+		aliases, err := sdk.LoadAliases(manager.RuntimeEnvContext, name)
+		if err != nil {
+			return err
+		}
+		for _, alias := range aliases {
+			result = append(result, &aliasOutput{Sdk: name, Name: alias.Name, Version: string(alias.Version)})
+		}
+	}
+	if isStructuredOutput(cmd) {
+		return printOutput(cmd, result)
+	}
+	if len(result) == 0 {
+		pterm.Println("No aliases defined, use `vfox alias set <sdk> <alias> <version>` to add one.")
+		return nil
+	}
+	data := pterm.TableData{{"SDK", "Alias", "Version"}}
+	for _, alias := range result {
+		data = append(data, []string{alias.Sdk, alias.Name, alias.Version})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+func aliasRemoveCmd(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 2 {
+		return cli.Exit("usage: vfox alias rm <sdk> <alias>", 1)
+	}
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	name, alias := strings.ToLower(cmd.Args().Get(0)), cmd.Args().Get(1)
+	if err = sdk.RemoveAlias(manager.RuntimeEnvContext, name, alias); err != nil {
+		return err
+	}
+	pterm.Printf("Removed alias %s\n", pterm.LightBlue(name+"@"+alias))
+	return nil
+}
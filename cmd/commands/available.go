@@ -23,7 +23,6 @@ import (
 
 	"github.com/pterm/pterm"
 	"github.com/urfave/cli/v3"
-	"github.com/version-fox/vfox/internal"
 )
 
 var Available = &cli.Command{
@@ -34,7 +33,7 @@ var Available = &cli.Command{
 }
 
 func availableCmd(ctx context.Context, cmd *cli.Command) error {
-	manager, err := internal.NewSdkManager()
+	manager, err := newOutputManager(cmd)
 	if err != nil {
 		return err
 	}
@@ -44,6 +43,18 @@ func availableCmd(ctx context.Context, cmd *cli.Command) error {
 	if err != nil {
 		return err
 	}
+	if isStructuredOutput(cmd) {
+		result := make([]*pluginOutput, 0, len(available))
+		for _, item := range available {
+			result = append(result, &pluginOutput{
+				Name:        item.Name,
+				Description: item.Desc,
+				Homepage:    item.Homepage,
+				Official:    isOfficialPlugin(item.Homepage),
+			})
+		}
+		return printOutput(cmd, result)
+	}
 
 	pterm.Println(pterm.Bold.Sprint("AVAILABLE PLUGINS"))
 	pterm.Println()
@@ -57,7 +68,7 @@ func availableCmd(ctx context.Context, cmd *cli.Command) error {
 	nameWidth := maxNameLen + 2
 
 	for _, item := range available {
-		isOfficial := strings.HasPrefix(item.Homepage, "https://github.com/version-fox/")
+		isOfficial := isOfficialPlugin(item.Homepage)
 
 		official := pterm.LightRed("✗")
 		if isOfficial {
@@ -73,3 +84,15 @@ func availableCmd(ctx context.Context, cmd *cli.Command) error {
 	return nil
 
 }
+
+// pluginOutput is the schema of a plugin of the registry.
+type pluginOutput struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Homepage    string `json:"homepage" yaml:"homepage"`
+	Official    bool   `json:"official" yaml:"official"`
+}
+
+func isOfficialPlugin(homepage string) bool {
+	return strings.HasPrefix(homepage, "https://github.com/version-fox/")
+}
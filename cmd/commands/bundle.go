@@ -0,0 +1,121 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v3"
+	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/env"
+)
+
+const defaultBundleFilename = "vfox-bundle.tar.zst"
+
+var Bundle = &cli.Command{
+	Name:  "bundle",
+	Usage: "Pack or unpack plugins and installed SDKs for offline machines",
+	Commands: []*cli.Command{
+		{
+			Name:  "create",
+			Usage: "Pack the plugins and SDK versions of the current .vfox.toml",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "out",
+					Aliases: []string{"o"},
+					Usage:   "path of the bundle to create",
+					Value:   defaultBundleFilename,
+				},
+			},
+			Action: bundleCreateCmd,
+		},
+		{
+			Name:      "import",
+			Usage:     "Unpack a bundle into the vfox root",
+			ArgsUsage: "<bundle>",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:    "yes",
+					Aliases: []string{"y"},
+					Usage:   "Approve the permissions of the imported plugins without prompting",
+				},
+			},
+			Action: bundleImportCmd,
+		},
+	},
+	Category: CategorySDK,
+}
+
+func bundleCreateCmd(ctx context.Context, cmd *cli.Command) error {
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	projectToml, err := manager.RuntimeEnvContext.LoadVfoxTomlByScope(env.Project)
+	if err != nil {
+		return err
+	}
+	tools := projectToml.GetAllTools()
+	_ = manager.ParseLegacyFile(manager.RuntimeEnvContext.CurrentWorkingDir, func(sdkname, version string) {
+		if _, ok := tools[sdkname]; !ok {
+			tools[sdkname] = version
+		}
+	})
+	if len(tools) == 0 {
+		return cli.Exit("no SDK configured in the current directory, use 'vfox use -p' first", 1)
+	}
+
+	dest := cmd.String("out")
+	manifest, err := manager.CreateBundle(dest, tools)
+	if err != nil {
+		return err
+	}
+	for _, runtime := range manifest.Runtimes {
+		pterm.Printf("Packed %s\n", pterm.LightGreen(runtime.Name+"@"+runtime.Version))
+	}
+	pterm.Printf("Bundle created at %s\n", pterm.LightBlue(dest))
+	return nil
+}
+
+func bundleImportCmd(ctx context.Context, cmd *cli.Command) error {
+	src := cmd.Args().First()
+	if src == "" {
+		return cli.Exit("bundle path is required", 1)
+	}
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	result, err := manager.ImportBundle(src, cmd.Bool("yes"))
+	if err != nil {
+		return fmt.Errorf("failed to import bundle: %w", err)
+	}
+	for _, name := range result.Imported {
+		pterm.Printf("Imported %s\n", pterm.LightGreen(name))
+	}
+	for _, name := range result.Skipped {
+		pterm.Printf("Skipped %s, already installed\n", pterm.LightYellow(name))
+	}
+	pterm.Printf("Bundle imported, use 'vfox use' to activate the SDKs.\n")
+	return nil
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/pterm/pterm"
 	"github.com/urfave/cli/v3"
 	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/sdk"
 )
 
 var Current = &cli.Command{
@@ -35,12 +37,15 @@ var Current = &cli.Command{
 }
 
 func currentCmd(ctx context.Context, cmd *cli.Command) error {
-	manager, err := internal.NewSdkManager()
+	manager, err := newOutputManager(cmd)
 	if err != nil {
 		return err
 	}
 	defer manager.Close()
 	sdkName := cmd.Args().First()
+	if isStructuredOutput(cmd) {
+		return currentStructured(cmd, manager, sdkName)
+	}
 	if sdkName == "" {
 		allSdk, err := manager.LoadAllSdk()
 		if err != nil {
@@ -70,3 +75,40 @@ func currentCmd(ctx context.Context, cmd *cli.Command) error {
 	pterm.Println("->", pterm.LightGreen("v"+string(current)))
 	return nil
 }
+
+func currentStructured(cmd *cli.Command, manager *internal.Manager, sdkName string) error {
+	chain, err := manager.RuntimeEnvContext.LoadVfoxTomlChainByScopes(env.Global, env.Session, env.Project)
+	if err != nil {
+		return err
+	}
+	if sdkName != "" {
+		source, err := manager.LookupSdk(sdkName)
+		if err != nil {
+			return fmt.Errorf("%s not supported, error: %w", sdkName, err)
+		}
+		current := source.Current()
+		if current == "" {
+			return fmt.Errorf("no current version of %s", sdkName)
+		}
+		return printOutput(cmd, newCurrentOutput(source, chain, current))
+	}
+	allSdk, err := manager.LoadAllSdk()
+	if err != nil {
+		return err
+	}
+	result := make([]*sdkVersionOutput, 0, len(allSdk))
+	for _, s := range allSdk {
+		result = append(result, newCurrentOutput(s, chain, s.Current()))
+	}
+	return printOutput(cmd, result)
+}
+
+// newCurrentOutput describes the current version of source, with an empty version if there is none.
+func newCurrentOutput(source sdk.Sdk, chain env.VfoxTomlChain, current sdk.Version) *sdkVersionOutput {
+	if current == "" {
+		return &sdkVersionOutput{Name: source.Metadata().Name}
+	}
+	out := newSdkVersionOutput(source, chain, current)
+	out.Current = true
+	return out
+}
@@ -0,0 +1,67 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v3"
+	"github.com/version-fox/vfox/internal/shared/util"
+)
+
+var Du = &cli.Command{
+	Name:  "du",
+	Usage: "Show the disk usage of installed SDKs, plugins, caches and temp files",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "top",
+			Usage: "only show the N largest entries",
+		},
+	},
+	Action:   duCmd,
+	Category: CategorySDK,
+}
+
+func duCmd(ctx context.Context, cmd *cli.Command) error {
+	manager, err := newOutputManager(cmd)
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	usage, err := manager.DiskUsage(ctx)
+	if err != nil {
+		return err
+	}
+	if top := cmd.Int("top"); top > 0 && top < len(usage.Entries) {
+		usage.Entries = usage.Entries[:top]
+	}
+	if isStructuredOutput(cmd) {
+		return printOutput(cmd, usage)
+	}
+
+	data := pterm.TableData{{"Type", "Name", "Size", "Path"}}
+	for _, entry := range usage.Entries {
+		data = append(data, []string{string(entry.Kind), entry.Name, util.FormatSize(entry.Size), entry.Path})
+	}
+	if err = pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+		return err
+	}
+	pterm.Printf("Total: %s\n", pterm.LightGreen(util.FormatSize(usage.Total)))
+	return nil
+}
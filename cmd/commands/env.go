@@ -22,7 +22,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sync"
+	"slices"
 
 	"github.com/urfave/cli/v3"
 	"github.com/version-fox/vfox/internal"
@@ -30,7 +30,6 @@ import (
 	"github.com/version-fox/vfox/internal/sdk"
 	"github.com/version-fox/vfox/internal/shared/logger"
 	"github.com/version-fox/vfox/internal/shell"
-	"golang.org/x/sync/errgroup"
 )
 
 var Env = &cli.Command{
@@ -55,6 +54,17 @@ var Env = &cli.Command{
 			Name:  "full",
 			Usage: "output full env",
 		},
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "output format: dotenv, github, systemd or dockerfile",
+			Validator: func(s string) error {
+				if slices.Contains(env.Formats, env.Format(s)) {
+					return nil
+				}
+				return fmt.Errorf("unsupported format %q, expected dotenv, github, systemd or dockerfile", s)
+			},
+		},
 	},
 	Action:   envCmd,
 	Category: CategorySDK,
@@ -63,6 +73,8 @@ var Env = &cli.Command{
 func envCmd(ctx context.Context, cmd *cli.Command) error {
 	if cmd.IsSet("json") {
 		return outputJSON()
+	} else if cmd.IsSet("format") {
+		return outputFormatted(ctx, env.Format(cmd.String("format")))
 	} else if cmd.IsSet("cleanup") {
 		return cleanTmp()
 	} else {
@@ -81,7 +93,7 @@ func outputJSON() error {
 		Paths:     []string{},
 		SDKs:      make(SDKs),
 	}
-	manager, err := internal.NewSdkManager()
+	manager, err := newShellOutputManager()
 	if err != nil {
 		return err
 	}
@@ -97,7 +109,7 @@ func outputJSON() error {
 	allTools := chain.GetAllTools()
 	for name, version := range allTools {
 		if lookupSdk, err := manager.LookupSdk(name); err == nil {
-			if runtimePackage, err := lookupSdk.GetRuntimePackage(sdk.Version(version)); err == nil {
+			if runtimePackage, err := lookupSdk.GetRuntimePackage(lookupSdk.ExpandAlias(sdk.Version(version))); err == nil {
 				if keys, err := lookupSdk.EnvKeys(runtimePackage); err == nil {
 					metadata := lookupSdk.Metadata()
 					data.SDKs[metadata.Name] = keys.Variables
@@ -114,6 +126,42 @@ func outputJSON() error {
 	return nil
 }
 
+// outputFormatted prints the envs of the current directory in the given format, using the real
+// install paths so that the output stays valid outside of the current shell session.
+func outputFormatted(ctx context.Context, format env.Format) error {
+	manager, err := newShellOutputManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	envs, err := resolveInstalledEnvs(ctx, manager)
+	if err != nil {
+		return err
+	}
+	if format == env.FormatGithub {
+		return env.WriteGithubEnv(envs)
+	}
+	_, cleanSystemPaths := manager.RuntimeEnvContext.SplitSystemPaths()
+	output, err := env.Render(format, envs, cleanSystemPaths)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// newShellOutputManager creates the manager of a command whose output is evaluated by the
+// shell or parsed, so that plugins print to stderr.
+func newShellOutputManager() (*internal.Manager, error) {
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		return nil, err
+	}
+	manager.RuntimeEnvContext.ShellOutput = true
+	return manager, nil
+}
+
 func cleanTmp() error {
 	manager, err := internal.NewSdkManager()
 	if err != nil {
@@ -135,7 +183,7 @@ func envFlag(cmd *cli.Command) error {
 	}
 
 	// Create manager
-	manager, err := internal.NewSdkManager()
+	manager, err := newShellOutputManager()
 	if err != nil {
 		return err
 	}
@@ -149,16 +197,10 @@ func envFlag(cmd *cli.Command) error {
 		return err
 	}
 
-	projectToml, _ := chain.GetTomlByScope(env.Project)
 	// Give a chance to legacy file to set tool versions if not already set in vfox.toml
 	// This allows backward compatibility with older projects using legacy files
 	// New projects should use vfox.toml directly
-	_ = manager.ParseLegacyFile(runtimeEnvContext.CurrentWorkingDir, func(sdkname, version string) {
-		// Set only if not already set in vfox.toml
-		if _, ok := projectToml.GetToolVersion(sdkname); !ok {
-			projectToml.SetTool(sdkname, version)
-		}
-	})
+	applyLegacyFiles(manager, chain)
 
 	// 2. Collect config file paths for change detection
 	// Only include paths that actually exist to avoid false positives
@@ -194,101 +236,26 @@ func envFlag(cmd *cli.Command) error {
 		cachedOutput := state.GetCachedOutput()
 		if cachedOutput != "" {
 			logger.Debugf("Using cached output")
+			for name, version := range state.GetCachedTools() {
+				manager.MarkUsed(name, sdk.Version(version))
+			}
 			fmt.Print(cachedOutput)
 			return nil
 		}
 	}
 
 	// 6. Slow path: recalculate env (full computation)
-	// Process each SDK concurrently (same logic as before)
-	allTools := chain.GetAllTools()
-	envsByScope := map[env.UseScope]*env.Envs{
-		env.Project: env.NewEnvs(),
-		env.Session: env.NewEnvs(),
-		env.Global:  env.NewEnvs(),
-	}
-	var mu sync.Mutex
-
-	// Process SDKs concurrently using errgroup
-	g, _ := errgroup.WithContext(context.Background())
-
-	for sdkName := range allTools {
-		sdkName := sdkName // Capture loop variable
-
-		g.Go(func() error {
-			// Lookup SDK
-			sdkObj, err := manager.LookupSdk(sdkName)
-			if err != nil {
-				logger.Debugf("SDK %s not found: %v", sdkName, err)
-				return nil // Continue processing other SDKs
-			}
-
-			// Resolve to the highest-priority installed version across scopes
-			toolConfig, scope, sdkVersion, ok := resolveInstalledToolConfig(chain, sdkObj, sdkName)
-			if !ok {
-				return nil
-			}
-
-			// Determine the actual scope to use
-			// - If the scope is Project but linking is not enabled, downgrade to Session
-			// - Global always uses link (no change)
-			// - Session always uses link (no change)
-			actualScope := scope
-			if env.Project == scope && sdk.IsUseUnLink(toolConfig.Attr) {
-				actualScope = env.Session
-			}
-
-			// Create symlinks if needed (internal logic checks if symlink already exists)
-			if err := sdkObj.CreateSymlinksForScope(sdkVersion, actualScope); err != nil {
-				logger.Debugf("Failed to create symlinks for %s@%s (scope: %s): %v",
-					sdkName, sdkVersion, actualScope.String(), err)
-				return nil // Continue processing other SDKs
-			}
-
-			// Get environment variables pointing to symlinks
-			sdkEnvs, err := sdkObj.EnvKeysForScope(sdkVersion, actualScope)
-			if err != nil {
-				logger.Debugf("Failed to get env keys for %s@%s: %v", sdkName, sdkVersion, err)
-				return nil // Continue processing other SDKs
-			}
-
-			// Collect envs by scope to ensure proper PATH priority (thread-safe)
-			mu.Lock()
-			envsByScope[actualScope].Merge(sdkEnvs)
-			mu.Unlock()
-
-			return nil
-		})
-	}
-
-	// Wait for all SDK processing to complete
-	if err := g.Wait(); err != nil {
+	tools, err := resolveScopedTools(context.Background(), manager, chain, true)
+	if err != nil {
 		return err
 	}
+	markToolsUsed(manager, tools)
 
-	// 7. Merge envs by scope priority: Project > Session > Global
-	// This ensures proper priority for both PATH (Project first) and Vars (Project overrides)
-	finalEnvs := env.NewEnvs()
-	scopePriority := []env.UseScope{env.Project, env.Session, env.Global}
-	finalEnvs.MergeByScopePriority(envsByScope, scopePriority)
-
-	// 8. Build final PATH with proper priority:
+	// 7. Build final envs with proper priority:
 	// User-injected paths (e.g., virtualenv) > Project > Session > Global > Cleaned System PATH
-	//
-	// SplitSystemPaths separates:
-	// - prefixPaths: paths appearing BEFORE first vfox path (user-injected, highest priority)
-	// - cleanSystemPaths: remaining non-vfox paths (lowest priority)
-	prefixPaths, cleanSystemPaths := runtimeEnvContext.SplitSystemPaths()
-
-	// Build final path order: prefix > vfox > clean system
-	// We need to prepend prefixPaths to maintain their highest priority
-	newPaths := env.NewPaths(env.EmptyPaths)
-	newPaths.Merge(prefixPaths)
-	newPaths.Merge(finalEnvs.Paths)
-	newPaths.Merge(cleanSystemPaths)
-	finalEnvs.Paths = newPaths
+	finalEnvs := buildScopedEnvs(runtimeEnvContext, tools)
 
-	// 9. Export environment variables
+	// 8. Export environment variables
 	if len(finalEnvs.Variables) == 0 && len(finalEnvs.Paths.Slice()) == 0 {
 		return nil
 	}
@@ -304,7 +271,8 @@ func envFlag(cmd *cli.Command) error {
 
 	exportStr := s.Export(exportEnvs)
 
-	// 10. Update state with new output
+	// 9. Update state with new output
+	state.SetCachedTools(toolVersions(tools))
 	if err := state.Update(configPaths, exportStr); err != nil {
 		logger.Debugf("Failed to update state: %v", err)
 	}
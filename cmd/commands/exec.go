@@ -180,12 +180,13 @@ func resolveExecSDKEnv(manager *internal.Manager, sdkSpec execSDKSpec) (*env.Env
 	if err != nil {
 		return nil, fmt.Errorf("failed to get env keys for %s@%s: %w", sdkSpec.Name, resolvedVersion, err)
 	}
+	manager.MarkUsed(sdkSpec.Name, resolvedVersion)
 	return envKeys, nil
 }
 
 func resolveExecSDKVersion(manager *internal.Manager, sdkSpec execSDKSpec) (sdk.Version, error) {
 	if sdkSpec.Version != "" {
-		return sdkSpec.Version, nil
+		return sdk.ExpandAlias(manager.RuntimeEnvContext, sdkSpec.Name, sdkSpec.Version), nil
 	}
 
 	chain, err := manager.RuntimeEnvContext.LoadVfoxTomlChainByScopes(env.Global, env.Session, env.Project)
@@ -197,7 +198,7 @@ func resolveExecSDKVersion(manager *internal.Manager, sdkSpec execSDKSpec) (sdk.
 	if !ok || version == "" {
 		return "", fmt.Errorf("no version configured for %s. Please use 'vfox use' to set a version first", sdkSpec.Name)
 	}
-	return sdk.Version(version), nil
+	return sdk.ExpandAlias(manager.RuntimeEnvContext, sdkSpec.Name, sdk.Version(version)), nil
 }
 
 func mergeExecEnvsByPriority(envsByPriority []*env.Envs) *env.Envs {
@@ -227,26 +228,7 @@ func applyExecSystemPaths(runtimeEnvContext *env.RuntimeEnvContext, sdkEnvs *env
 
 // executeCommand executes a command in the specified environment
 func executeCommand(command string, args []string, envMap map[string]string) error {
-	// Build environment variable array
-	envVars := os.Environ()
-
-	// First, clean vfox paths from PATH
-	cleanedEnvVars := make([]string, 0, len(envVars))
-	for _, env := range envVars {
-		// Skip environment variables containing vfox sdk paths (checked via PathMeta.IsVfoxRelatedPath)
-		if strings.HasPrefix(env, "PATH=") {
-			// Keep system PATH, vfox will add correct PATH via envMap
-			cleanedEnvVars = append(cleanedEnvVars, env)
-		} else if !strings.HasPrefix(env, "VFOX_") {
-			// Keep non-vfox related environment variables
-			cleanedEnvVars = append(cleanedEnvVars, env)
-		}
-	}
-
-	// Add vfox managed environment variables (note: PATH will override system PATH)
-	for key, value := range envMap {
-		cleanedEnvVars = append(cleanedEnvVars, fmt.Sprintf("%s=%s", key, value))
-	}
+	cleanedEnvVars := buildCommandEnv(envMap)
 
 	// Build temporary environment for finding executable
 	tmpEnv := make(map[string]string)
@@ -274,6 +256,38 @@ func executeCommand(command string, args []string, envMap map[string]string) err
 	return execCmd.Run()
 }
 
+// buildCommandEnv returns the current process environment with vfox internal
+// variables removed and the given vfox managed variables applied on top.
+func buildCommandEnv(envMap map[string]string) []string {
+	// Build environment variable array
+	envVars := os.Environ()
+
+	// First, clean vfox paths from PATH
+	cleanedEnvVars := make([]string, 0, len(envVars)+len(envMap))
+	for _, env := range envVars {
+		// Skip variables overridden by envMap, syscall.Exec does not deduplicate them
+		if key, _, ok := strings.Cut(env, "="); ok {
+			if _, overridden := envMap[key]; overridden {
+				continue
+			}
+		}
+		// Skip environment variables containing vfox sdk paths (checked via PathMeta.IsVfoxRelatedPath)
+		if strings.HasPrefix(env, "PATH=") {
+			// Keep system PATH, vfox will add correct PATH via envMap
+			cleanedEnvVars = append(cleanedEnvVars, env)
+		} else if !strings.HasPrefix(env, "VFOX_") {
+			// Keep non-vfox related environment variables
+			cleanedEnvVars = append(cleanedEnvVars, env)
+		}
+	}
+
+	// Add vfox managed environment variables (note: PATH will override system PATH)
+	for key, value := range envMap {
+		cleanedEnvVars = append(cleanedEnvVars, fmt.Sprintf("%s=%s", key, value))
+	}
+	return cleanedEnvVars
+}
+
 // lookPathInEnv searches for executable file in specified PATH environment variable
 func lookPathInEnv(command, pathEnv string) (string, error) {
 	if pathEnv == "" {
@@ -19,7 +19,6 @@ package commands
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 	"strings"
 	"text/template"
 
@@ -45,7 +44,7 @@ var Info = &cli.Command{
 }
 
 func infoCmd(ctx context.Context, cmd *cli.Command) error {
-	manager, err := internal.NewSdkManager()
+	manager, err := newOutputManager(cmd)
 	if err != nil {
 		return err
 	}
@@ -64,50 +63,7 @@ func infoCmd(ctx context.Context, cmd *cli.Command) error {
 
 			// Check for empty SDK name or version
 			if name != "" && string(version) != "" {
-				sdkSource, err := manager.LookupSdk(name)
-				if err != nil {
-					if cmd.IsSet("format") {
-						// For template output, we still need to output something
-						data := struct {
-							Name    string
-							Version string
-							Path    string
-						}{
-							Name:    name,
-							Version: string(version),
-							Path:    "notfound",
-						}
-						return executeTemplate(cmd, data)
-					}
-					fmt.Println("notfound")
-					return nil
-				}
-				path := ""
-				runtimePackage, err := sdkSource.GetRuntimePackage(version)
-				if err == nil {
-					// TODO check
-					path = filepath.Join(runtimePackage.PackagePath)
-				} else {
-					path = "notfound"
-				}
-
-				// Check if format flag is set
-				formatValue := cmd.String("format")
-				if formatValue != "" {
-					data := struct {
-						Name    string
-						Version string
-						Path    string
-					}{
-						Name:    name,
-						Version: string(version),
-						Path:    path,
-					}
-					return executeTemplate(cmd, data)
-				}
-
-				fmt.Println(path)
-				return nil
+				return infoVersion(cmd, manager, name, manager.ResolveVersion(name, version))
 			}
 		}
 	}
@@ -147,6 +103,64 @@ func infoCmd(ctx context.Context, cmd *cli.Command) error {
 	return nil
 }
 
+// versionInfo is the data of `vfox info <sdk>@<version>`, for templates and structured output.
+type versionInfo struct {
+	Name    string       `json:"name" yaml:"name"`
+	Version string       `json:"version" yaml:"version"`
+	Path    string       `json:"path" yaml:"path"`
+	Receipt *sdk.Receipt `json:"receipt,omitempty" yaml:"receipt,omitempty"`
+}
+
+func infoVersion(cmd *cli.Command, manager *internal.Manager, name string, version sdk.Version) error {
+	data := &versionInfo{
+		Name:    name,
+		Version: string(version),
+		Path:    "notfound",
+	}
+	if sdkSource, err := manager.LookupSdk(name); err == nil {
+		if runtimePackage, err := sdkSource.GetRuntimePackage(version); err == nil {
+			data.Path = runtimePackage.PackagePath
+			data.Receipt, _ = sdkSource.Receipt(version)
+		}
+	}
+
+	if cmd.IsSet("format") {
+		return executeTemplate(cmd, data)
+	}
+	if isStructuredOutput(cmd) {
+		return printOutput(cmd, data)
+	}
+	if data.Receipt == nil {
+		fmt.Println(data.Path)
+		return nil
+	}
+	printReceipt(data.Path, data.Receipt)
+	return nil
+}
+
+func printReceipt(path string, receipt *sdk.Receipt) {
+	pterm.Println("Path     ", "->", pterm.LightBlue(path))
+	pterm.Println("Plugin   ", "->", pterm.LightBlue(receipt.PluginName+"@"+receipt.PluginVersion))
+	pterm.Println("Installed", "->", pterm.LightBlue(receipt.InstalledAt.Local().Format("2006-01-02 15:04:05")+" by vfox "+receipt.VfoxVersion))
+	items := append([]*sdk.ReceiptItem{receipt.Main}, receipt.Additions...)
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		pterm.Println()
+		pterm.Println(pterm.LightGreen(item.Name + "@" + item.Version))
+		if item.Source != "" {
+			pterm.Println("  Source  ", "->", item.Source)
+		}
+		if item.Checksum != "" {
+			pterm.Println("  Checksum", "->", item.Checksum)
+		}
+		if item.Note != "" {
+			pterm.Println("  Note    ", "->", item.Note)
+		}
+	}
+}
+
 func executeTemplate(cmd *cli.Command, data interface{}) error {
 	tmplStr := cmd.String("format")
 	tmpl, err := template.New("format").Parse(tmplStr)
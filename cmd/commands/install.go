@@ -71,6 +71,7 @@ func installCmd(ctx context.Context, cmd *cli.Command) error {
 	defer manager.Close()
 
 	errorStore := util.NewErrorStore()
+	installed := false
 
 	for i := 0; i < args.Len(); i++ {
 		sdkArg := args.Get(i)
@@ -124,7 +125,7 @@ func installCmd(ctx context.Context, cmd *cli.Command) error {
 				}
 				showAvailable, _ := pterm.DefaultInteractiveConfirm.Show(fmt.Sprintf("No %s version provided, do you want to select a version to install?", pterm.Red(name)))
 				if showAvailable {
-					err := RunSearch(name, []string{})
+					err := RunSearch(name, []string{}, false)
 					if err != nil {
 						errorStore.AddAndShow(name, err)
 					}
@@ -135,10 +136,15 @@ func installCmd(ctx context.Context, cmd *cli.Command) error {
 					errorStore.AddAndShow(name, err)
 					continue
 				}
+				installed = true
 			}
 		}
 	}
 
+	if installed {
+		manager.RefreshShims()
+	}
+
 	notes := errorStore.GetNotesSet()
 
 	if notes.Len() == 1 {
@@ -191,20 +197,13 @@ func installAll(autoConfirm bool) error {
 		pluginsResult = make(map[string]bool)
 		sdksResult    = make(map[string]bool)
 	)
-	os.Stdout = nil
-	os.Stderr = nil
-	pterm.SetDefaultOutput(os.Stdout)
-
-	spinnerInfo, _ := pterm.DefaultSpinner.
-		WithSequence([]string{"⣾ ", "⣽ ", "⣻ ", "⢿ ", "⡿ ", "⣟ ", "⣯ ", "⣷ "}...).
-		WithText("Installing...").
-		WithWriter(stdout).
-		Start()
+	// Plugins are added before the spinner starts, so that the permissions each of them
+	// declares are shown and approved like with `vfox add`.
 	for _, plugin := range plugins {
 		index++
-		spinnerInfo.UpdateText(fmt.Sprintf("[%v/%v] %s: %s installing...\033[K", index, count, "Plugin", plugin))
+		fmt.Printf("[%v/%v] Plugin: %s\n", index, count, plugin)
 		pluginsResult[plugin] = false
-		if err := manager.Add(plugin, "", ""); err != nil {
+		if err := manager.Add(plugin, "", "", autoConfirm); err != nil {
 			if errors.Is(err, internal.ManifestNotFound) {
 				errorStr = fmt.Sprintf("%s\n[%s] not found in remote registry, please check the name", errorStr, plugin)
 			} else {
@@ -214,6 +213,16 @@ func installAll(autoConfirm bool) error {
 		}
 		pluginsResult[plugin] = true
 	}
+
+	os.Stdout = nil
+	os.Stderr = nil
+	pterm.SetDefaultOutput(os.Stdout)
+
+	spinnerInfo, _ := pterm.DefaultSpinner.
+		WithSequence([]string{"⣾ ", "⣽ ", "⣻ ", "⢿ ", "⡿ ", "⣟ ", "⣯ ", "⣷ "}...).
+		WithText("Installing...").
+		WithWriter(stdout).
+		Start()
 	for sdkName, version := range sdks {
 		index++
 		spinnerInfo.UpdateText(fmt.Sprintf("[%v/%v] %s: %s@%s installing...\033[K", index, count, "SDK", sdkName, version))
@@ -231,6 +240,7 @@ func installAll(autoConfirm bool) error {
 		}
 		sdksResult[sdkVersion] = true
 	}
+	manager.RefreshShims()
 	spinnerInfo.UpdateText(fmt.Sprintf("[%v/%v] Installation completed.\033[K", count, count))
 	_ = spinnerInfo.Stop()
 	os.Stdout = stdout
@@ -265,9 +275,9 @@ func notInstalled(manager *internal.Manager) (plugins []string, sdks map[string]
 		if err != nil {
 			// Plugin not installed
 			plugins = append(plugins, name)
-		} else if !lookupSdk.CheckRuntimeExist(sdk.Version(version)) {
+		} else if expanded := lookupSdk.ExpandAlias(sdk.Version(version)); !lookupSdk.CheckRuntimeExist(expanded) {
 			// SDK not installed
-			sdks[name] = version
+			sdks[name] = string(expanded)
 		}
 	}
 	return
@@ -19,28 +19,42 @@ package commands
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pterm/pterm"
 	"github.com/pterm/pterm/putils"
 	"github.com/urfave/cli/v3"
 	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/sdk"
+	"github.com/version-fox/vfox/internal/shared/util"
 )
 
 var List = &cli.Command{
-	Name:     "list",
-	Aliases:  []string{"ls"},
-	Usage:    "List all versions of the target SDK",
-	Action:   listCmd,
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List all versions of the target SDK",
+	Action:  listCmd,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "verbose",
+			Usage: "Show the last-used time and size of every version",
+		},
+	},
 	Category: CategorySDK,
 }
 
 func listCmd(ctx context.Context, cmd *cli.Command) error {
-	manager, err := internal.NewSdkManager()
+	manager, err := newOutputManager(cmd)
 	if err != nil {
 		return err
 	}
 	defer manager.Close()
 	sdkName := cmd.Args().First()
+	verbose := cmd.Bool("verbose")
+	if isStructuredOutput(cmd) {
+		return listStructured(cmd, manager, sdkName, verbose)
+	}
 	if sdkName == "" {
 		allSdk, err := manager.LoadAllSdk()
 		if err != nil {
@@ -55,7 +69,11 @@ func listCmd(ctx context.Context, cmd *cli.Command) error {
 			name := s.Metadata().Name
 			tree = append(tree, pterm.LeveledListItem{Level: 0, Text: name})
 			for _, version := range s.InstalledList() {
-				tree = append(tree, pterm.LeveledListItem{Level: 1, Text: string(version)})
+				text := string(version)
+				if verbose {
+					text += " " + pterm.Gray(newVersionUsage(manager, s, version).String())
+				}
+				tree = append(tree, pterm.LeveledListItem{Level: 1, Text: text})
 			}
 		}
 		// Generate tree from LeveledList.
@@ -74,6 +92,9 @@ func listCmd(ctx context.Context, cmd *cli.Command) error {
 	if len(list) == 0 {
 		return fmt.Errorf("no available version")
 	}
+	if verbose {
+		return listVerbose(manager, source, list, curVersion)
+	}
 	for _, version := range list {
 		if version == curVersion {
 			pterm.Println("->", fmt.Sprintf("%s", version), pterm.LightGreen("<— current"))
@@ -83,3 +104,94 @@ func listCmd(ctx context.Context, cmd *cli.Command) error {
 	}
 	return nil
 }
+
+// sdkListOutput is the schema of the installed versions of an SDK.
+type sdkListOutput struct {
+	Name     string              `json:"name" yaml:"name"`
+	Current  string              `json:"current,omitempty" yaml:"current,omitempty"`
+	Versions []*sdkVersionOutput `json:"versions" yaml:"versions"`
+}
+
+// versionUsage is the last-used time and size of an installed version.
+type versionUsage struct {
+	LastUsed *time.Time
+	Size     int64
+}
+
+func newVersionUsage(manager *internal.Manager, source sdk.Sdk, version sdk.Version) *versionUsage {
+	usage := &versionUsage{}
+	if t, ok := manager.LastUsed(source.Metadata().Name, version); ok {
+		usage.LastUsed = &t
+	}
+	if runtimePackage, err := source.GetRuntimePackage(version); err == nil {
+		usage.Size, _ = util.DirSize(runtimePackage.PackagePath)
+	}
+	return usage
+}
+
+func (u *versionUsage) lastUsedText() string {
+	if u.LastUsed == nil {
+		return "never"
+	}
+	return u.LastUsed.Format("2006-01-02 15:04")
+}
+
+func (u *versionUsage) String() string {
+	return fmt.Sprintf("(last used: %s, size: %s)", u.lastUsedText(), util.FormatSize(u.Size))
+}
+
+func listVerbose(manager *internal.Manager, source sdk.Sdk, versions []sdk.Version, current sdk.Version) error {
+	data := pterm.TableData{{"Version", "Last Used", "Size", ""}}
+	for _, version := range versions {
+		usage := newVersionUsage(manager, source, version)
+		mark := ""
+		if version == current {
+			mark = pterm.LightGreen("<— current")
+		}
+		data = append(data, []string{string(version), usage.lastUsedText(), util.FormatSize(usage.Size), mark})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+func listStructured(cmd *cli.Command, manager *internal.Manager, sdkName string, verbose bool) error {
+	chain, err := manager.RuntimeEnvContext.LoadVfoxTomlChainByScopes(env.Global, env.Session, env.Project)
+	if err != nil {
+		return err
+	}
+	if sdkName != "" {
+		source, err := manager.LookupSdk(sdkName)
+		if err != nil {
+			return fmt.Errorf("%s not supported, error: %w", sdkName, err)
+		}
+		return printOutput(cmd, newSdkListOutput(manager, source, chain, verbose))
+	}
+	allSdk, err := manager.LoadAllSdk()
+	if err != nil {
+		return err
+	}
+	result := make([]*sdkListOutput, 0, len(allSdk))
+	for _, s := range allSdk {
+		result = append(result, newSdkListOutput(manager, s, chain, verbose))
+	}
+	return printOutput(cmd, result)
+}
+
+func newSdkListOutput(manager *internal.Manager, source sdk.Sdk, chain env.VfoxTomlChain, verbose bool) *sdkListOutput {
+	current := source.Current()
+	out := &sdkListOutput{
+		Name:     source.Metadata().Name,
+		Current:  string(current),
+		Versions: []*sdkVersionOutput{},
+	}
+	for _, version := range source.InstalledList() {
+		item := newSdkVersionOutput(source, chain, version)
+		item.Current = version == current
+		if verbose {
+			usage := newVersionUsage(manager, source, version)
+			item.LastUsed = usage.LastUsed
+			item.Size = usage.Size
+		}
+		out.Versions = append(out.Versions, item)
+	}
+	return out
+}
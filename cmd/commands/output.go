@@ -0,0 +1,171 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat is the format of the data printed by read commands.
+type OutputFormat string
+
+const (
+	OutputText OutputFormat = "text"
+	OutputJSON OutputFormat = "json"
+	OutputYAML OutputFormat = "yaml"
+)
+
+// OutputFlag is the global flag selecting the output format of read commands.
+var OutputFlag = &cli.StringFlag{
+	Name:  "output",
+	Usage: "output format: text, json or yaml",
+	Value: string(OutputText),
+	Validator: func(s string) error {
+		switch OutputFormat(s) {
+		case OutputText, OutputJSON, OutputYAML:
+			return nil
+		default:
+			return fmt.Errorf("unsupported output format %q, expected text, json or yaml", s)
+		}
+	},
+}
+
+// outputFormat returns the output format selected for the command.
+func outputFormat(cmd *cli.Command) OutputFormat {
+	if format := OutputFormat(cmd.String(OutputFlag.Name)); format != "" {
+		return format
+	}
+	return OutputText
+}
+
+// isStructuredOutput reports whether the command should print machine-readable output.
+func isStructuredOutput(cmd *cli.Command) bool {
+	return outputFormat(cmd) != OutputText
+}
+
+// newOutputManager creates the manager of cmd. Plugins print to stderr while cmd writes
+// structured output, so that it stays parseable.
+func newOutputManager(cmd *cli.Command) (*internal.Manager, error) {
+	if isStructuredOutput(cmd) {
+		return newShellOutputManager()
+	}
+	return internal.NewSdkManager()
+}
+
+// writeStructured encodes v to w in the given format.
+func writeStructured(w io.Writer, format OutputFormat, v any) error {
+	switch format {
+	case OutputYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+}
+
+// printOutput prints v in the output format selected for the command.
+func printOutput(cmd *cli.Command, v any) error {
+	return writeStructured(os.Stdout, outputFormat(cmd), v)
+}
+
+// errorOutput is the schema of errors printed in structured output mode.
+type errorOutput struct {
+	Error    string `json:"error" yaml:"error"`
+	ExitCode int    `json:"exit_code" yaml:"exit_code"`
+}
+
+// HandleExitError is the ExitErrHandler of the root command. In structured output mode
+// errors are left to PrintError, otherwise exit coders are handled as usual.
+func HandleExitError(ctx context.Context, cmd *cli.Command, err error) {
+	if isStructuredOutput(cmd) {
+		return
+	}
+	cli.HandleExitCoder(err)
+}
+
+// PrintError prints err in the output format selected for the command and
+// returns the exit code the process should exit with.
+func PrintError(cmd *cli.Command, err error) int {
+	code := 1
+	var exitCoder cli.ExitCoder
+	if errors.As(err, &exitCoder) {
+		code = exitCoder.ExitCode()
+	}
+	// An exit coder without message only sets the exit code, the command already
+	// reported the failure.
+	if err.Error() == "" {
+		return code
+	}
+	if !isStructuredOutput(cmd) {
+		fmt.Println(err)
+		return code
+	}
+	_ = writeStructured(os.Stdout, outputFormat(cmd), &errorOutput{Error: err.Error(), ExitCode: code})
+	return code
+}
+
+// sdkVersionOutput is the schema of an installed SDK version.
+type sdkVersionOutput struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	Current bool   `json:"current" yaml:"current"`
+	// Scope is the highest-priority scope whose config selects this version.
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Path  string `json:"path,omitempty" yaml:"path,omitempty"`
+	Note  string `json:"note,omitempty" yaml:"note,omitempty"`
+	// LastUsed and Size are only set by `list --verbose`.
+	LastUsed *time.Time `json:"last_used,omitempty" yaml:"last_used,omitempty"`
+	Size     int64      `json:"size,omitempty" yaml:"size,omitempty"`
+}
+
+// newSdkVersionOutput describes an installed version of source, looking up its scope in chain.
+func newSdkVersionOutput(source sdk.Sdk, chain env.VfoxTomlChain, version sdk.Version) *sdkVersionOutput {
+	name := source.Metadata().Name
+	out := &sdkVersionOutput{
+		Name:    name,
+		Version: string(version),
+	}
+	for _, toolConfig := range chain.GetToolConfigsByPriority(name) {
+		if sdk.Version(toolConfig.Config.Version) == version {
+			out.Scope = toolConfig.Scope.String()
+			break
+		}
+	}
+	if runtimePackage, err := source.GetRuntimePackage(version); err == nil {
+		out.Path = runtimePackage.Path
+		out.Note = runtimePackage.Note
+	}
+	return out
+}
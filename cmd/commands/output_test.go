@@ -0,0 +1,79 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteStructuredSdkList(t *testing.T) {
+	list := &sdkListOutput{
+		Name:    "nodejs",
+		Current: "20.1.0",
+		Versions: []*sdkVersionOutput{
+			{Name: "nodejs", Version: "20.1.0", Current: true, Scope: "project", Path: "/tmp/nodejs-20.1.0", Note: "LTS"},
+			{Name: "nodejs", Version: "18.0.0"},
+		},
+	}
+
+	var jsonBuf bytes.Buffer
+	if err := writeStructured(&jsonBuf, OutputJSON, list); err != nil {
+		t.Fatalf("writeStructured(json) failed: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(jsonBuf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid json output: %v\n%s", err, jsonBuf.String())
+	}
+	versions := decoded["versions"].([]any)
+	first := versions[0].(map[string]any)
+	if first["scope"] != "project" || first["path"] != "/tmp/nodejs-20.1.0" || first["current"] != true || first["note"] != "LTS" {
+		t.Fatalf("unexpected json version entry: %v", first)
+	}
+	if _, ok := versions[1].(map[string]any)["scope"]; ok {
+		t.Fatalf("expected empty scope to be omitted: %s", jsonBuf.String())
+	}
+
+	var yamlBuf bytes.Buffer
+	if err := writeStructured(&yamlBuf, OutputYAML, list); err != nil {
+		t.Fatalf("writeStructured(yaml) failed: %v", err)
+	}
+	var decodedYaml sdkListOutput
+	if err := yaml.Unmarshal(yamlBuf.Bytes(), &decodedYaml); err != nil {
+		t.Fatalf("invalid yaml output: %v\n%s", err, yamlBuf.String())
+	}
+	if decodedYaml.Current != "20.1.0" || len(decodedYaml.Versions) != 2 {
+		t.Fatalf("unexpected yaml output:\n%s", yamlBuf.String())
+	}
+}
+
+func TestWriteStructuredError(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeStructured(&buf, OutputJSON, &errorOutput{Error: "boom", ExitCode: 2}); err != nil {
+		t.Fatalf("writeStructured failed: %v", err)
+	}
+	var decoded errorOutput
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid json output: %v", err)
+	}
+	if decoded.Error != "boom" || decoded.ExitCode != 2 {
+		t.Fatalf("unexpected error output: %+v", decoded)
+	}
+}
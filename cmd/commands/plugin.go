@@ -0,0 +1,173 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v3"
+	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/plugin/devkit"
+)
+
+var Plugin = &cli.Command{
+	Name:  "plugin",
+	Usage: "Develop plugins",
+	Commands: []*cli.Command{
+		{
+			Name:      "new",
+			Usage:     "Create a plugin with a metadata file and all hooks",
+			ArgsUsage: "<name>",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "dir",
+					Usage: "Directory to create the plugin in, defaults to vfox-<name>",
+				},
+			},
+			Action: pluginNewCmd,
+		},
+		{
+			Name:      "test",
+			Usage:     "Run every hook of a plugin with sample contexts, serving HTTP from recorded fixtures",
+			ArgsUsage: "<dir>",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "version",
+					Usage: "Version passed to the hooks, defaults to the first available version",
+				},
+				&cli.BoolFlag{
+					Name:  "record",
+					Usage: "Send HTTP requests to the real servers and record the responses as fixtures",
+				},
+			},
+			Action: pluginTestCmd,
+		},
+	},
+	Category: CategoryPlugin,
+}
+
+// pluginTestOutput is the schema of the result of a hook in structured output.
+type pluginTestOutput struct {
+	Hook       string `json:"hook" yaml:"hook"`
+	Status     string `json:"status" yaml:"status"` // ok, failed or skipped
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms" yaml:"duration_ms"`
+	Output     any    `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+func pluginNewCmd(ctx context.Context, cmd *cli.Command) error {
+	name := cmd.Args().First()
+	if name == "" {
+		return cli.Exit("plugin name is required", 1)
+	}
+	dir := cmd.String("dir")
+	if dir == "" {
+		dir = "vfox-" + name
+	}
+	files, err := devkit.Scaffold(dir, name, internal.RuntimeVersion)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	for _, f := range files {
+		fmt.Printf("  %s %s\n", pterm.LightGreen("created"), f)
+	}
+	fmt.Printf("Run %s to try it.\n", pterm.LightBlue("vfox plugin test "+filepath.Clean(dir)))
+	return nil
+}
+
+func pluginTestCmd(ctx context.Context, cmd *cli.Command) error {
+	dir := cmd.Args().First()
+	if dir == "" {
+		return cli.Exit("plugin directory is required", 1)
+	}
+	manager, err := newOutputManager(cmd)
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	report, err := devkit.Run(dir, manager.RuntimeEnvContext, devkit.Options{
+		Version: cmd.String("version"),
+		Record:  cmd.Bool("record"),
+	})
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	if isStructuredOutput(cmd) {
+		result := make([]*pluginTestOutput, 0, len(report.Hooks))
+		for _, h := range report.Hooks {
+			out := &pluginTestOutput{Hook: h.Hook, Status: "ok", DurationMs: h.Duration.Milliseconds(), Output: h.Output}
+			if h.Skipped {
+				out.Status = "skipped"
+			} else if h.Err != nil {
+				out.Status, out.Error = "failed", h.Err.Error()
+			}
+			result = append(result, out)
+		}
+		if err = printOutput(cmd, result); err != nil {
+			return err
+		}
+	} else {
+		printPluginTest(report)
+	}
+	if report.Failed() {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+func printPluginTest(report *devkit.Report) {
+	fmt.Printf("Testing %s@%s\n", pterm.LightBlue(report.Plugin.Name), report.Plugin.Version)
+	for _, h := range report.Hooks {
+		switch {
+		case h.Skipped:
+			fmt.Printf("  %s %s %s\n", pterm.Gray("-"), h.Hook, pterm.Gray("(skipped)"))
+		case h.Err != nil:
+			fmt.Printf("  %s %s: %v\n", pterm.LightRed("✗"), h.Hook, h.Err)
+		default:
+			fmt.Printf("  %s %s %s %s\n", pterm.LightGreen("✓"), h.Hook, pterm.Gray(h.Duration.Round(time.Millisecond).String()), summarizeHookOutput(h.Output))
+		}
+	}
+	if report.Recorded > 0 {
+		fmt.Printf("Recorded %d HTTP fixtures in %s\n", report.Recorded, devkit.FixturesFilename)
+	}
+	for _, miss := range report.Misses {
+		fmt.Printf("%s no fixture for %s\n", pterm.LightYellow("WARNING"), miss)
+	}
+}
+
+// summarizeHookOutput returns the start of the JSON encoding of output.
+func summarizeHookOutput(output any) string {
+	if output == nil {
+		return ""
+	}
+	content, err := json.Marshal(output)
+	if err != nil {
+		return ""
+	}
+	const maxLen = 80
+	if len(content) > maxLen {
+		return string(content[:maxLen]) + "..."
+	}
+	return string(content)
+}
@@ -0,0 +1,56 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/sdk"
+)
+
+// RefreshCache refreshes stale caches in the background, started by the commands serving them.
+var RefreshCache = &cli.Command{
+	Name:   sdk.RefreshCacheCommand,
+	Hidden: true,
+	Commands: []*cli.Command{
+		{
+			Name:            "available",
+			SkipFlagParsing: true,
+			Action:          refreshAvailableCacheCmd,
+		},
+	},
+}
+
+func refreshAvailableCacheCmd(ctx context.Context, cmd *cli.Command) error {
+	sdkName := cmd.Args().First()
+	if sdkName == "" {
+		return cli.Exit("sdk name is required", 1)
+	}
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+	source, err := manager.LookupSdk(sdkName)
+	if err != nil {
+		return err
+	}
+	_, err = source.RefreshAvailable(cmd.Args().Tail())
+	return err
+}
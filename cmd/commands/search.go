@@ -32,13 +32,27 @@ import (
 )
 
 var Search = &cli.Command{
-	Name:     "search",
-	Usage:    "Search a version of the target SDK",
+	Name:  "search",
+	Usage: "Search a version of the target SDK",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "refresh",
+			Usage: "Ignore cached results and ask the plugin again",
+		},
+	},
 	Action:   searchCmd,
 	Category: CategorySDK,
 }
 
-func RunSearch(sdkName string, availableArgs []string) error {
+// searchAvailable lists the available versions of source, bypassing the cache if refresh is set.
+func searchAvailable(source sdk.Sdk, availableArgs []string, refresh bool) ([]*sdk.AvailableRuntimePackage, error) {
+	if refresh {
+		return source.RefreshAvailable(availableArgs)
+	}
+	return source.Available(availableArgs)
+}
+
+func RunSearch(sdkName string, availableArgs []string, refresh bool) error {
 	manager, err := internal.NewSdkManager()
 	if err != nil {
 		return err
@@ -48,7 +62,7 @@ func RunSearch(sdkName string, availableArgs []string) error {
 	if err != nil {
 		return fmt.Errorf("%s not supported, error: %w", sdkName, err)
 	}
-	result, err := source.Available(availableArgs)
+	result, err := searchAvailable(source, availableArgs, refresh)
 	if err != nil {
 		return fmt.Errorf("plugin [Available] method error: %w", err)
 	}
@@ -131,5 +145,64 @@ func searchCmd(ctx context.Context, cmd *cli.Command) error {
 	if sdkName == "" {
 		return cli.Exit("sdk name is required", 1)
 	}
-	return RunSearch(sdkName, cmd.Args().Tail())
+	if isStructuredOutput(cmd) {
+		return searchStructured(cmd, sdkName, cmd.Args().Tail())
+	}
+	return RunSearch(sdkName, cmd.Args().Tail(), cmd.Bool("refresh"))
+}
+
+// searchOutput is the schema of the available versions of an SDK.
+type searchOutput struct {
+	Name     string                 `json:"name" yaml:"name"`
+	Versions []*searchVersionOutput `json:"versions" yaml:"versions"`
+}
+
+type searchVersionOutput struct {
+	// Name is only set for additions, which are runtimes installed alongside the version.
+	Name      string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Version   string                 `json:"version" yaml:"version"`
+	Note      string                 `json:"note,omitempty" yaml:"note,omitempty"`
+	Installed bool                   `json:"installed" yaml:"installed"`
+	Additions []*searchVersionOutput `json:"additions,omitempty" yaml:"additions,omitempty"`
+}
+
+func searchStructured(cmd *cli.Command, sdkName string, availableArgs []string) error {
+	manager, err := newOutputManager(cmd)
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+	source, err := manager.LookupSdk(sdkName)
+	if err != nil {
+		return fmt.Errorf("%s not supported, error: %w", sdkName, err)
+	}
+	result, err := searchAvailable(source, availableArgs, cmd.Bool("refresh"))
+	if err != nil {
+		return fmt.Errorf("plugin [Available] method error: %w", err)
+	}
+
+	installedVersions := util.NewSet[sdk.Version]()
+	for _, version := range source.InstalledList() {
+		installedVersions.Add(version)
+	}
+	out := &searchOutput{
+		Name:     source.Metadata().Name,
+		Versions: make([]*searchVersionOutput, 0, len(result)),
+	}
+	for _, p := range result {
+		item := &searchVersionOutput{
+			Version:   string(p.Version),
+			Note:      p.Note,
+			Installed: installedVersions.Contains(p.Version),
+		}
+		for _, a := range p.Additions {
+			item.Additions = append(item.Additions, &searchVersionOutput{
+				Name:    a.Name,
+				Version: string(a.Version),
+				Note:    a.Note,
+			})
+		}
+		out.Versions = append(out.Versions, item)
+	}
+	return printOutput(cmd, out)
 }
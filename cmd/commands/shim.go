@@ -0,0 +1,104 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v3"
+	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/shared/logger"
+)
+
+var Reshim = &cli.Command{
+	Name:     "reshim",
+	Usage:    "Regenerate shims for all installed SDKs into ~/.vfox/shims",
+	Action:   reshimCmd,
+	Category: CategorySDK,
+}
+
+var ShimExec = &cli.Command{
+	Name:            internal.ShimExecCommand,
+	Hidden:          true,
+	SkipFlagParsing: true,
+	Action:          shimExecCmd,
+}
+
+func reshimCmd(ctx context.Context, cmd *cli.Command) error {
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	count, err := manager.Reshim()
+	if err != nil {
+		return err
+	}
+	shimsDir := manager.RuntimeEnvContext.PathMeta.User.Shims
+	pterm.Printf("Generated %d shims in %s\n", count, pterm.LightBlue(shimsDir))
+	if !strings.Contains(os.Getenv("PATH"), shimsDir) {
+		pterm.Printf("Add %s to your PATH to use them outside of a hooked shell.\n", pterm.LightBlue(shimsDir))
+	}
+	return nil
+}
+
+func shimExecCmd(ctx context.Context, cmd *cli.Command) error {
+	name := cmd.Args().First()
+	if name == "" {
+		return cli.Exit("shim name is required", 1)
+	}
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	envs, err := resolveInstalledEnvs(ctx, manager)
+	if err != nil {
+		return err
+	}
+	applyExecSystemPaths(manager.RuntimeEnvContext, envs)
+
+	envMap := make(map[string]string, len(envs.Variables)+1)
+	for key, value := range envs.Variables {
+		if value != nil {
+			envMap[key] = *value
+		}
+	}
+	envMap["PATH"] = envs.Paths.String()
+
+	// The shims directory lives under the vfox home and has been stripped from PATH
+	// by SplitSystemPaths, so the lookup can never resolve back to the shim itself.
+	_ = os.Setenv("PATH", envMap["PATH"])
+	execPath, err := exec.LookPath(name)
+	if err != nil {
+		if providers := manager.ShimProviders(name); len(providers) > 0 {
+			return cli.Exit(fmt.Sprintf("%s is provided by %s, but no installed version is configured. Use 'vfox use' to set one", name, strings.Join(providers, ", ")), 1)
+		}
+		return cli.Exit(fmt.Sprintf("command not found: %s", name), 1)
+	}
+	logger.Debugf("Shim %s resolved to %s\n", name, execPath)
+
+	manager.Close()
+	return execShim(execPath, cmd.Args().Tail(), buildCommandEnv(envMap))
+}
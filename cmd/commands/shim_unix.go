@@ -0,0 +1,31 @@
+//go:build !windows
+
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"path/filepath"
+	"syscall"
+)
+
+// execShim replaces the current process with the target executable, so signals
+// and the exit code go straight to the caller of the shim.
+func execShim(path string, args []string, env []string) error {
+	argv := append([]string{filepath.Base(path)}, args...)
+	return syscall.Exec(path, argv, env)
+}
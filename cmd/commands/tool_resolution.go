@@ -17,12 +17,33 @@
 package commands
 
 import (
+	"context"
+	"sync"
+
+	"github.com/version-fox/vfox/internal"
 	"github.com/version-fox/vfox/internal/env"
 	"github.com/version-fox/vfox/internal/pathmeta"
 	"github.com/version-fox/vfox/internal/sdk"
 	"github.com/version-fox/vfox/internal/shared/logger"
+	"golang.org/x/sync/errgroup"
 )
 
+// scopePriority is the order in which scoped envs are merged: Project > Session > Global.
+var scopePriority = []env.UseScope{env.Project, env.Session, env.Global}
+
+// scopedTool is an installed SDK version selected by the vfox.toml chain,
+// together with the envs it contributes to the shell.
+type scopedTool struct {
+	Name    string
+	Version sdk.Version
+	// ConfigScope is the scope of the config that selected the version.
+	ConfigScope env.UseScope
+	// Scope is the scope the SDK is linked into, which differs from ConfigScope
+	// for project configs that are not linked.
+	Scope env.UseScope
+	Envs  *env.Envs
+}
+
 func resolveInstalledToolConfig(chain env.VfoxTomlChain, sdkObj sdk.Sdk, sdkName string) (*pathmeta.ToolConfig, env.UseScope, sdk.Version, bool) {
 	toolConfigs := chain.GetToolConfigsByPriority(sdkName)
 	if len(toolConfigs) == 0 {
@@ -30,7 +51,7 @@ func resolveInstalledToolConfig(chain env.VfoxTomlChain, sdkObj sdk.Sdk, sdkName
 	}
 
 	for _, toolConfig := range toolConfigs {
-		version := sdk.Version(toolConfig.Config.Version)
+		version := sdkObj.ExpandAlias(sdk.Version(toolConfig.Config.Version))
 		if sdkObj.CheckRuntimeExist(version) {
 			return toolConfig.Config, toolConfig.Scope, version, true
 		}
@@ -41,3 +62,165 @@ func resolveInstalledToolConfig(chain env.VfoxTomlChain, sdkObj sdk.Sdk, sdkName
 	logger.Debugf("No installed configured version found for SDK %s", sdkName)
 	return nil, env.Global, "", false
 }
+
+// applyLegacyFiles gives legacy version files a chance to set tool versions
+// in the project config, without overriding what vfox.toml already sets.
+func applyLegacyFiles(manager *internal.Manager, chain env.VfoxTomlChain) {
+	projectToml, ok := chain.GetTomlByScope(env.Project)
+	if !ok || projectToml == nil {
+		return
+	}
+	_ = manager.ParseLegacyFile(manager.RuntimeEnvContext.CurrentWorkingDir, func(sdkname, version string) {
+		if _, ok := projectToml.GetToolVersion(sdkname); !ok {
+			projectToml.SetTool(sdkname, version)
+		}
+	})
+}
+
+// resolveScopedTools resolves every tool of the chain to its highest-priority installed
+// version concurrently. With link, each tool is linked into its scope and its envs point
+// to the links; otherwise its envs point to the real install paths and Scope is ConfigScope.
+// Tools that cannot be resolved are skipped.
+func resolveScopedTools(ctx context.Context, manager *internal.Manager, chain env.VfoxTomlChain, link bool) ([]*scopedTool, error) {
+	var (
+		tools []*scopedTool
+		mu    sync.Mutex
+	)
+	g, _ := errgroup.WithContext(ctx)
+
+	for sdkName := range chain.GetAllTools() {
+		g.Go(func() error {
+			sdkObj, err := manager.LookupSdk(sdkName)
+			if err != nil {
+				logger.Debugf("SDK %s not found: %v\n", sdkName, err)
+				return nil
+			}
+
+			// Resolve to the highest-priority installed version across scopes
+			toolConfig, scope, sdkVersion, ok := resolveInstalledToolConfig(chain, sdkObj, sdkName)
+			if !ok {
+				return nil
+			}
+
+			var (
+				actualScope = scope
+				sdkEnvs     *env.Envs
+			)
+			if link {
+				// Determine the actual scope to use
+				// - If the scope is Project but linking is not enabled, downgrade to Session
+				// - Global always uses link (no change)
+				// - Session always uses link (no change)
+				if env.Project == scope && sdk.IsUseUnLink(toolConfig.Attr) {
+					actualScope = env.Session
+				}
+
+				// Create symlinks if needed (internal logic checks if symlink already exists)
+				if err := sdkObj.CreateSymlinksForScope(sdkVersion, actualScope); err != nil {
+					logger.Debugf("Failed to create symlinks for %s@%s (scope: %s): %v\n",
+						sdkName, sdkVersion, actualScope.String(), err)
+					return nil
+				}
+
+				// Get environment variables pointing to symlinks
+				sdkEnvs, err = sdkObj.EnvKeysForScope(sdkVersion, actualScope)
+			} else {
+				var runtimePackage *sdk.RuntimePackage
+				runtimePackage, err = sdkObj.GetRuntimePackage(sdkVersion)
+				if err != nil {
+					logger.Debugf("Failed to get runtime package for %s@%s: %v\n", sdkName, sdkVersion, err)
+					return nil
+				}
+				sdkEnvs, err = sdkObj.EnvKeys(runtimePackage)
+			}
+			if err != nil {
+				logger.Debugf("Failed to get env keys for %s@%s: %v\n", sdkName, sdkVersion, err)
+				return nil
+			}
+
+			mu.Lock()
+			tools = append(tools, &scopedTool{
+				Name:        sdkObj.Metadata().Name,
+				Version:     sdkVersion,
+				ConfigScope: scope,
+				Scope:       actualScope,
+				Envs:        sdkEnvs,
+			})
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return tools, nil
+}
+
+// buildScopedEnvs merges the envs of the tools by scope priority and assembles the final PATH:
+// user-injected paths (e.g., virtualenv) > Project > Session > Global > cleaned system PATH.
+func buildScopedEnvs(runtimeEnvContext *env.RuntimeEnvContext, tools []*scopedTool) *env.Envs {
+	finalEnvs := mergeScopedEnvs(tools)
+
+	// SplitSystemPaths separates:
+	// - prefixPaths: paths appearing BEFORE first vfox path (user-injected, highest priority)
+	// - cleanSystemPaths: remaining non-vfox paths (lowest priority)
+	prefixPaths, cleanSystemPaths := runtimeEnvContext.SplitSystemPaths()
+
+	newPaths := env.NewPaths(env.EmptyPaths)
+	newPaths.Merge(prefixPaths)
+	newPaths.Merge(finalEnvs.Paths)
+	newPaths.Merge(cleanSystemPaths)
+	finalEnvs.Paths = newPaths
+	return finalEnvs
+}
+
+// mergeScopedEnvs merges the envs of the tools by scope priority, Project overriding both
+// PATH order and variables.
+func mergeScopedEnvs(tools []*scopedTool) *env.Envs {
+	envsByScope := map[env.UseScope]*env.Envs{
+		env.Project: env.NewEnvs(),
+		env.Session: env.NewEnvs(),
+		env.Global:  env.NewEnvs(),
+	}
+	for _, tool := range tools {
+		envsByScope[tool.Scope].Merge(tool.Envs)
+	}
+	finalEnvs := env.NewEnvs()
+	finalEnvs.MergeByScopePriority(envsByScope, scopePriority)
+	return finalEnvs
+}
+
+// markToolsUsed records the resolved tools as used, see Manager.MarkUsed.
+func markToolsUsed(manager *internal.Manager, tools []*scopedTool) {
+	for _, tool := range tools {
+		manager.MarkUsed(tool.Name, tool.Version)
+	}
+}
+
+// toolVersions maps the name of every tool to its version.
+func toolVersions(tools []*scopedTool) map[string]string {
+	versions := make(map[string]string, len(tools))
+	for _, tool := range tools {
+		versions[tool.Name] = string(tool.Version)
+	}
+	return versions
+}
+
+// resolveInstalledEnvs builds the envs of the configured and installed SDKs like the shell
+// hook does, but pointing to the real install paths instead of scope symlinks, which may
+// not exist outside of a hooked shell. System paths are not included.
+func resolveInstalledEnvs(ctx context.Context, manager *internal.Manager) (*env.Envs, error) {
+	chain, err := manager.RuntimeEnvContext.LoadVfoxTomlChainByScopes(env.Global, env.Session, env.Project)
+	if err != nil {
+		return nil, err
+	}
+	applyLegacyFiles(manager, chain)
+
+	tools, err := resolveScopedTools(ctx, manager, chain, false)
+	if err != nil {
+		return nil, err
+	}
+	markToolsUsed(manager, tools)
+	return mergeScopedEnvs(tools), nil
+}
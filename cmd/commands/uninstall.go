@@ -19,7 +19,6 @@ package commands
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/pterm/pterm"
@@ -65,9 +64,10 @@ func uninstallCmd(ctx context.Context, cmd *cli.Command) error {
 	if err = source.Uninstall(resolvedVersion); err != nil {
 		return err
 	}
+	manager.RefreshShims()
 	remainVersion := source.InstalledList()
 	if len(remainVersion) == 0 {
-		_ = os.RemoveAll(source.Metadata().SdkInstalledPath)
+		sdk.CleanInstallPath(source.Metadata().SdkInstalledPath)
 		return nil
 	}
 	if cv == version {
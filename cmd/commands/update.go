@@ -36,6 +36,11 @@ var Update = &cli.Command{
 			Aliases: []string{"a"},
 			Usage:   "all plugins flag",
 		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "Approve new permissions of the plugins without prompting",
+		},
 	},
 	Action:   updateCmd,
 	Category: CategoryPlugin,
@@ -47,6 +52,7 @@ func updateCmd(ctx context.Context, cmd *cli.Command) error {
 		return err
 	}
 	defer manager.Close()
+	yes := cmd.Bool("yes")
 	if cmd.Bool(allFlag) {
 		if sdks, err := manager.LoadAllSdk(); err == nil {
 			var (
@@ -57,7 +63,7 @@ func updateCmd(ctx context.Context, cmd *cli.Command) error {
 				sdkName := s.Metadata().Name
 				index++
 				pterm.Printf("[%s/%d]: Updating %s plugin...\n", pterm.Green(index), total, pterm.Green(sdkName))
-				if err = manager.Update(sdkName); err != nil {
+				if err = manager.Update(sdkName, yes); err != nil {
 					pterm.Println(fmt.Sprintf("Update plugin(%s) failed, %s", sdkName, err.Error()))
 				}
 			}
@@ -71,7 +77,7 @@ func updateCmd(ctx context.Context, cmd *cli.Command) error {
 			return cli.Exit("invalid arguments", 1)
 		}
 
-		return manager.Update(args.First())
+		return manager.Update(args.First(), yes)
 	}
 	return nil
 }
@@ -18,200 +18,141 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
 	"runtime"
-	"strings"
 
 	"github.com/urfave/cli/v3"
 	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/release"
 	"github.com/version-fox/vfox/internal/shared/util"
 )
 
 const SelfUpgradeName = "upgrade"
 
 var Upgrade = &cli.Command{
-	Name:   SelfUpgradeName,
-	Usage:  "upgrade vfox to the latest version",
+	Name:  SelfUpgradeName,
+	Usage: "upgrade vfox to the latest version",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "channel",
+			Usage: "Release channel to upgrade from: stable or beta, defaults to upgrade.channel",
+		},
+		&cli.BoolFlag{
+			Name:  "rollback",
+			Usage: "Restore the binary replaced by the last upgrade",
+		},
+	},
 	Action: upgradeCmd,
 }
 
-func fetchLatestVersion(c *http.Client) (string, error) {
-	resp, err := c.Get("https://github.com/version-fox/vfox/tags")
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	re, err := regexp.Compile(`href="/version-fox/vfox/releases/tag/(v[0-9.]+)"`)
+func upgradeCmd(ctx context.Context, cmd *cli.Command) error {
+	exePath, err := os.Executable()
 	if err != nil {
-		return "", err
-	}
-	matches := re.FindAllStringSubmatch(string(body), -1)
-
-	if len(matches) == 0 {
-		return "", fmt.Errorf("Failed to fetch the version.")
-	}
-
-	latestVersion := matches[0][1]
-	return latestVersion, nil
-}
-
-func constructBinaryName(tagName string) string {
-	osType := strings.ToLower(runtime.GOOS)
-	if osType == "darwin" {
-		osType = "macos"
-	}
-
-	archType := runtime.GOARCH
-	if archType == "arm64" {
-		archType = "aarch64"
-	}
-	if archType == "amd64" {
-		archType = "x86_64"
-	}
-
-	extName := "tar.gz"
-	if osType == "windows" {
-		extName = "zip"
+		return cli.Exit("Failed to get executable path: "+err.Error(), 1)
 	}
-
-	fileName := fmt.Sprintf("vfox_%s_%s_%s.%s", tagName[1:], osType, archType, extName)
-	return fileName
-}
-
-func generateUrls(currVersion string, tagName string) (string, string) {
-	fileName := constructBinaryName(tagName)
-	binURL := fmt.Sprintf("https://github.com/version-fox/vfox/releases/download/%s/%s", tagName, fileName)
-	diffURL := fmt.Sprintf("https://github.com/version-fox/vfox/compare/%s...%s", currVersion, tagName)
-	return binURL, diffURL
-}
-
-func downloadFile(c *http.Client, filepath string, url string) error {
-	out, err := os.Create(filepath)
-	if err != nil {
-		return err
+	if exePath, err = filepath.EvalSymlinks(exePath); err != nil {
+		return cli.Exit("Failed to resolve executable path: "+err.Error(), 1)
 	}
-	defer out.Close()
-	resp, err := c.Get(url)
-	if err != nil {
-		return err
+	if cmd.Bool("rollback") {
+		return rollbackCmd(exePath)
 	}
-	defer resp.Body.Close()
-	_, err = io.Copy(out, resp.Body)
-	return err
-}
 
-func upgradeCmd(ctx context.Context, cmd *cli.Command) error {
 	manager, err := internal.NewSdkManager()
 	if err != nil {
 		return err
 	}
 	defer manager.Close()
 	httpClient := manager.RuntimeEnvContext.HttpClient()
+	upgradeConfig := manager.RuntimeEnvContext.UserConfig.Upgrade
 
-	currVersion := fmt.Sprintf("v%s", internal.RuntimeVersion)
-	latestVersion, err := fetchLatestVersion(httpClient)
+	channel := cmd.String("channel")
+	if channel == "" {
+		channel = upgradeConfig.Channel
+	}
+	manifest, err := release.FetchManifest(httpClient, upgradeConfig.ManifestUrl)
 	if err != nil {
 		return cli.Exit("Failed to fetch the latest version: "+err.Error(), 1)
 	}
+	latest, err := manifest.Channel(channel)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	currVersion := fmt.Sprintf("v%s", internal.RuntimeVersion)
 	fmt.Println("Current version: ", currVersion)
-	fmt.Println("Latest available:", latestVersion)
-	if currVersion == latestVersion {
+	fmt.Printf("Latest available: %s (%s)\n", latest.Version, channel)
+	if !release.IsNewer(latest.Version, currVersion) {
 		return cli.Exit("vfox is already up to date.", 0)
 	}
-	if err = RequestPermission(); err != nil {
-		return err
-	}
-	exePath, err := os.Executable()
+	asset, err := latest.CurrentAsset()
 	if err != nil {
-		return cli.Exit("Failed to get executable path: "+err.Error(), 1)
+		return cli.Exit(err.Error(), 1)
 	}
-	exeDir, exeName := filepath.Split(exePath)
-	binURL, diffURL := generateUrls(currVersion, latestVersion)
-	tempFile := "vfox_latest.tar.gz"
-	if runtime.GOOS == "windows" {
-		tempFile = "vfox_latest.zip"
+	if err = RequestPermission(); err != nil {
+		return err
 	}
-	tempDir := filepath.Join(exeDir, "vfox_upgrade")
-	tempFile = filepath.Join(tempDir, tempFile)
 
-	// create tempDir if not exist
-	if _, err := os.Stat(tempDir); os.IsNotExist(err) {
-		if err := os.Mkdir(tempDir, 0755); err != nil {
-			return cli.Exit("Failed to create directory: "+err.Error(), 1)
-		}
-	} else if err != nil {
-		return cli.Exit("Error checking directory: "+err.Error(), 1)
-	} else {
-		fmt.Println("Directory already exists")
+	// Stay next to the executable, so that the new binary can be renamed into place.
+	exeDir, exeName := filepath.Split(exePath)
+	tempDir, err := os.MkdirTemp(exeDir, ".vfox_upgrade-")
+	if err != nil {
+		return cli.Exit("Failed to create directory: "+err.Error(), 1)
 	}
-
 	defer func() {
 		if err := os.RemoveAll(tempDir); err != nil {
 			fmt.Println("Error removing directory: ", err)
 		}
 	}()
 
-	fmt.Println("Fetching", binURL)
-
-	if err := downloadFile(httpClient, tempFile, binURL); err != nil {
+	fmt.Println("Fetching", asset.URL)
+	archive := filepath.Join(tempDir, asset.Name)
+	if err = latest.Download(httpClient, asset, archive); err != nil {
 		return cli.Exit("Failed to download file: "+err.Error(), 1)
 	}
-	decompressor := util.NewDecompressor(tempFile)
-	if err := decompressor.Decompress(tempDir); err != nil {
+	fmt.Println("Checksum verified")
+	extractDir := filepath.Join(tempDir, "extract")
+	if err = util.NewDecompressor(archive).Decompress(extractDir); err != nil {
 		return cli.Exit("Failed to extract file: "+err.Error(), 1)
 	}
-	tempExePath := filepath.Join(tempDir, exeName)
-	if _, err := os.Stat(tempExePath); err != nil {
+	newExePath := filepath.Join(extractDir, exeName)
+	if _, err = os.Stat(newExePath); err != nil {
 		return cli.Exit("Failed to find valid executable: "+err.Error(), 1)
 	}
+	if err = release.Replace(exePath, newExePath); err != nil {
+		return cli.Exit("Failed to replace executable: "+err.Error(), 1)
+	}
 
-	if runtime.GOOS == "windows" {
-		backupExePath := filepath.Join(exeDir, "."+exeName)
-		batchFile := filepath.Join(exeDir, ".upgrade.bat")
-		if err := os.Rename(exePath, backupExePath); err != nil {
-			return cli.Exit("Failed to backup: "+err.Error(), 1)
-		}
-		if err := os.Rename(tempExePath, exePath); err != nil {
-			os.Rename(backupExePath, exePath)
-			return cli.Exit("Failed to replace executable: "+err.Error(), 1)
-		}
-		batchContent := fmt.Sprintf(":Repeat\n"+
-			"del \"%s\"\n"+
-			"if exist \"%s\" goto Repeat\n"+
-			"del \"%s\"", backupExePath, backupExePath, batchFile)
-		if err := os.WriteFile(batchFile, []byte(batchContent), 0666); err != nil {
-			return cli.Exit("Failed to clear: "+err.Error(), 1)
-		}
-		cmd := exec.Command("cmd.exe", "/C", batchFile)
-		if err := cmd.Start(); err != nil {
-			return cli.Exit("Failed to launch shell: "+err.Error(), 1)
-		}
-	} else {
-		if err := os.Rename(tempExePath, exePath); err != nil {
-			return cli.Exit("Failed to replace executable: "+err.Error(), 1)
-		}
-		if err := os.Chmod(exePath, 0755); err != nil {
-			return cli.Exit("Failed to make executable: "+err.Error(), 1)
-		}
+	fmt.Printf("Updated to version: %s\n", latest.Version)
+	if latest.NotesURL != "" {
+		fmt.Printf("See the release notes at: %s\n", latest.NotesURL)
 	}
+	fmt.Printf("Run 'vfox %s --rollback' to go back to %s.\n", SelfUpgradeName, currVersion)
+	waitOnWindows()
+	return nil
+}
 
-	fmt.Printf("Updated to version: %s\nSee the diff at: %s\n", latestVersion, diffURL)
+func rollbackCmd(exePath string) error {
+	if err := RequestPermission(); err != nil {
+		return err
+	}
+	if err := release.Rollback(exePath); err != nil {
+		if errors.Is(err, release.ErrNoPreviousBinary) {
+			return cli.Exit(err.Error(), 1)
+		}
+		return cli.Exit("Failed to roll back: "+err.Error(), 1)
+	}
+	fmt.Printf("Rolled back from version v%s.\n", internal.RuntimeVersion)
+	waitOnWindows()
+	return nil
+}
 
+// waitOnWindows keeps the elevated console window open until the user has read the result.
+func waitOnWindows() {
 	if runtime.GOOS == "windows" {
 		fmt.Println("Press any key to continue...")
 		var b = make([]byte, 1)
 		_, _ = os.Stdin.Read(b)
 	}
-	return nil
 }
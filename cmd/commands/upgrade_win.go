@@ -21,6 +21,7 @@ package commands
 import (
 	"fmt"
 	"os"
+	"strings"
 	"syscall"
 	"unsafe"
 
@@ -64,7 +65,8 @@ func runAsAdmin() error {
 	if err != nil {
 		return err
 	}
-	arg, err := syscall.UTF16PtrFromString(SelfUpgradeName)
+	// Keep the flags, such as --rollback, of the elevated run.
+	arg, err := syscall.UTF16PtrFromString(strings.Join(os.Args[1:], " "))
 	if err != nil {
 		return err
 	}
@@ -74,7 +74,7 @@ func useCmd(ctx context.Context, cmd *cli.Command) error {
 	// Determine scope
 	scope := determineScopeFromFlags(cmd)
 
-	manager, err := internal.NewSdkManager()
+	manager, err := newOutputManager(cmd)
 	if err != nil {
 		return err
 	}
@@ -96,7 +96,29 @@ func useCmd(ctx context.Context, cmd *cli.Command) error {
 	unlink := cmd.IsSet("unlink")
 
 	// Execute use operation
-	return sdkSource.UseWithConfig(resolvedVersion, scope, unlink)
+	if !isStructuredOutput(cmd) {
+		if err = sdkSource.UseWithConfig(resolvedVersion, scope, unlink); err != nil {
+			return err
+		}
+		manager.MarkUsed(name, resolvedVersion)
+		return nil
+	}
+
+	// Keep stdout parseable, the result is printed below instead
+	pterm.DisableOutput()
+	err = sdkSource.UseWithConfig(resolvedVersion, scope, unlink)
+	pterm.EnableOutput()
+	if err != nil {
+		return err
+	}
+	manager.MarkUsed(name, resolvedVersion)
+	chain, err := manager.RuntimeEnvContext.LoadVfoxTomlChainByScopes(env.Global, env.Session, env.Project)
+	if err != nil {
+		return err
+	}
+	out := newSdkVersionOutput(sdkSource, chain, resolvedVersion)
+	out.Current = true
+	return printOutput(cmd, out)
 }
 
 // parseSdkArg parses the SDK argument in format "name@version"
@@ -0,0 +1,164 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v3"
+	"github.com/version-fox/vfox/internal/sdk"
+)
+
+var Verify = &cli.Command{
+	Name:      "verify",
+	Usage:     "Check installed SDKs for files changed since installation",
+	ArgsUsage: "[<sdk> | <sdk>@<version>]",
+	Action:    verifyCmd,
+	Category:  CategorySDK,
+}
+
+const (
+	verifyStatusOK         = "ok"
+	verifyStatusDrift      = "drift"
+	verifyStatusUnverified = "unverified"
+)
+
+// verifyOutput is the schema of the verification of an installed SDK version.
+type verifyOutput struct {
+	Name     string   `json:"name" yaml:"name"`
+	Version  string   `json:"version" yaml:"version"`
+	Status   string   `json:"status" yaml:"status"`
+	Added    []string `json:"added" yaml:"added"`
+	Removed  []string `json:"removed" yaml:"removed"`
+	Modified []string `json:"modified" yaml:"modified"`
+}
+
+func verifyCmd(ctx context.Context, cmd *cli.Command) error {
+	manager, err := newOutputManager(cmd)
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	var sources []sdk.Sdk
+	var version sdk.Version
+	if arg := cmd.Args().First(); arg != "" {
+		name, v, _ := strings.Cut(arg, "@")
+		name = strings.ToLower(name)
+		source, err := manager.LookupSdk(name)
+		if err != nil {
+			return fmt.Errorf("%s not supported, error: %w", name, err)
+		}
+		if v != "" {
+			version = manager.ResolveVersion(name, sdk.Version(v))
+			if !source.CheckRuntimeExist(version) {
+				return cli.Exit(fmt.Sprintf("%s@%s is not installed", name, version), 1)
+			}
+		}
+		sources = append(sources, source)
+	} else if sources, err = manager.LoadAllSdk(); err != nil {
+		return err
+	}
+
+	results := make([]*verifyOutput, 0)
+	for _, source := range sources {
+		versions := source.InstalledList()
+		if version != "" {
+			versions = []sdk.Version{version}
+		}
+		for _, v := range versions {
+			result, err := verifyVersion(source, v)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+	}
+
+	if isStructuredOutput(cmd) {
+		if err = printOutput(cmd, results); err != nil {
+			return err
+		}
+	} else {
+		printVerify(results)
+	}
+	for _, result := range results {
+		if result.Status == verifyStatusDrift {
+			// The report was printed already, only the exit code is left.
+			return cli.Exit("", 1)
+		}
+	}
+	return nil
+}
+
+func verifyVersion(source sdk.Sdk, version sdk.Version) (*verifyOutput, error) {
+	out := &verifyOutput{
+		Name:     source.Metadata().Name,
+		Version:  string(version),
+		Status:   verifyStatusOK,
+		Added:    []string{},
+		Removed:  []string{},
+		Modified: []string{},
+	}
+	result, err := source.Verify(version)
+	if errors.Is(err, sdk.ErrFileManifestNotFound) {
+		out.Status = verifyStatusUnverified
+		return out, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify %s@%s: %w", out.Name, version, err)
+	}
+	if result.HasDrift() {
+		out.Status = verifyStatusDrift
+		out.Added = append(out.Added, result.Added...)
+		out.Removed = append(out.Removed, result.Removed...)
+		out.Modified = append(out.Modified, result.Modified...)
+	}
+	return out, nil
+}
+
+func printVerify(results []*verifyOutput) {
+	if len(results) == 0 {
+		pterm.Println("No SDK installed.")
+		return
+	}
+	for _, result := range results {
+		label := result.Name + "@" + result.Version
+		switch result.Status {
+		case verifyStatusOK:
+			pterm.Printf("%s %s\n", pterm.LightGreen("OK     "), label)
+		case verifyStatusUnverified:
+			pterm.Printf("%s %s %s\n", pterm.LightYellow("SKIPPED"), label, pterm.Gray("(installed without a file manifest)"))
+		default:
+			pterm.Printf("%s %s %s\n", pterm.LightRed("CHANGED"), label,
+				pterm.Gray(fmt.Sprintf("(%d modified, %d added, %d removed)", len(result.Modified), len(result.Added), len(result.Removed))))
+			for _, path := range result.Modified {
+				pterm.Printf("  M %s\n", path)
+			}
+			for _, path := range result.Added {
+				pterm.Printf("  A %s\n", path)
+			}
+			for _, path := range result.Removed {
+				pterm.Printf("  D %s\n", path)
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v3"
+	"github.com/version-fox/vfox/internal/env"
+)
+
+var Which = &cli.Command{
+	Name:      "which",
+	Usage:     "Show which SDK and version provides a command",
+	ArgsUsage: "<command>",
+	Action:    whichCmd,
+	Category:  CategorySDK,
+}
+
+// whichResult describes where a command resolves to in the vfox managed PATH.
+type whichResult struct {
+	Command string
+	// Path is the first match in PATH, Target is Path with symlinks resolved.
+	Path   string
+	Target string
+	// Tool is the SDK owning Path, nil for system binaries.
+	Tool *scopedTool
+	// ConfigPath is the config file that selected the version of Tool.
+	ConfigPath string
+	// Shadowed lists the matches of other PATH entries hidden by Path.
+	Shadowed []string
+	// HiddenTool is the SDK whose match is hidden by a system binary placed
+	// before the vfox paths, e.g. by a virtualenv.
+	HiddenTool *scopedTool
+}
+
+func whichCmd(ctx context.Context, cmd *cli.Command) error {
+	command := cmd.Args().First()
+	if command == "" {
+		return cli.Exit("command name is required", 1)
+	}
+	manager, err := newShellOutputManager()
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	runtimeEnvContext := manager.RuntimeEnvContext
+	chain, err := runtimeEnvContext.LoadVfoxTomlChainByScopes(env.Global, env.Session, env.Project)
+	if err != nil {
+		return err
+	}
+	applyLegacyFiles(manager, chain)
+
+	tools, err := resolveScopedTools(ctx, manager, chain, true)
+	if err != nil {
+		return err
+	}
+	finalEnvs := buildScopedEnvs(runtimeEnvContext, tools)
+
+	result, ok := resolveWhich(command, finalEnvs.Paths.Slice(), tools)
+	if !ok {
+		return cli.Exit(fmt.Sprintf("%s not found in PATH", command), 1)
+	}
+	if result.Tool != nil {
+		if toml, ok := chain.GetTomlByScope(result.Tool.ConfigScope); ok && toml != nil && toml.Path != "" {
+			if _, err := os.Stat(toml.Path); err == nil {
+				result.ConfigPath = toml.Path
+			}
+		}
+	}
+	printWhich(result)
+	return nil
+}
+
+// resolveWhich looks up command in paths in order and attributes the match to the tool
+// whose env contributed the PATH entry.
+func resolveWhich(command string, paths []string, tools []*scopedTool) (*whichResult, bool) {
+	owners := make(map[string]*scopedTool)
+	for _, tool := range tools {
+		for _, p := range tool.Envs.Paths.Slice() {
+			owners[filepath.Clean(p)] = tool
+		}
+	}
+
+	var result *whichResult
+	for _, dir := range paths {
+		if dir == "" {
+			continue
+		}
+		path, ok := findExecutableInDir(dir, command)
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = &whichResult{
+				Command: command,
+				Path:    path,
+				Target:  path,
+				Tool:    owners[filepath.Clean(dir)],
+			}
+			if target, err := filepath.EvalSymlinks(path); err == nil {
+				result.Target = target
+			}
+			continue
+		}
+		result.Shadowed = append(result.Shadowed, path)
+		if owner := owners[filepath.Clean(dir)]; result.Tool == nil && result.HiddenTool == nil {
+			result.HiddenTool = owner
+		}
+	}
+	return result, result != nil
+}
+
+// findExecutableInDir returns the path of command in dir, trying PATHEXT on Windows.
+func findExecutableInDir(dir, command string) (string, bool) {
+	candidates := []string{command}
+	if runtime.GOOS == "windows" && filepath.Ext(command) == "" {
+		pathExt := os.Getenv("PATHEXT")
+		if pathExt == "" {
+			pathExt = ".COM;.EXE;.BAT;.CMD"
+		}
+		candidates = nil
+		for _, ext := range strings.Split(pathExt, ";") {
+			if ext != "" {
+				candidates = append(candidates, command+strings.ToLower(ext))
+			}
+		}
+	}
+	for _, name := range candidates {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+			continue
+		}
+		return path, true
+	}
+	return "", false
+}
+
+func printWhich(result *whichResult) {
+	pterm.Println(result.Path)
+	if result.Target != result.Path {
+		pterm.Printf("  Target:  %s\n", result.Target)
+	}
+	if result.Tool == nil {
+		pterm.Printf("  Source:  %s\n", "system binary, not managed by vfox")
+		if hidden := result.HiddenTool; hidden != nil {
+			pterm.Printf("  vfox is not shadowing it, %s@%s (%s) is hidden behind it\n",
+				hidden.Name, hidden.Version, hidden.ConfigScope.String())
+		} else {
+			pterm.Printf("  vfox is not shadowing it\n")
+		}
+		return
+	}
+
+	tool := result.Tool
+	pterm.Printf("  SDK:     %s\n", pterm.LightBlue(tool.Name))
+	pterm.Printf("  Version: %s\n", pterm.LightGreen(string(tool.Version)))
+	scope := tool.ConfigScope.String()
+	if tool.Scope != tool.ConfigScope {
+		scope = fmt.Sprintf("%s (linked in %s)", scope, tool.Scope.String())
+	}
+	pterm.Printf("  Scope:   %s\n", scope)
+	if result.ConfigPath != "" {
+		pterm.Printf("  Config:  %s\n", result.ConfigPath)
+	} else if tool.ConfigScope == env.Project {
+		pterm.Printf("  Config:  %s\n", "legacy version file")
+	}
+	if len(result.Shadowed) > 0 {
+		pterm.Printf("  Shadows: %s\n", strings.Join(result.Shadowed, ", "))
+	} else {
+		pterm.Printf("  No system binary is shadowed\n")
+	}
+}
@@ -0,0 +1,97 @@
+//go:build !windows
+
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/version-fox/vfox/internal/env"
+)
+
+func writeExecutable(t *testing.T, dir, name string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", dir, err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+func newScopedTool(name string, scope env.UseScope, dirs ...string) *scopedTool {
+	envs := env.NewEnvs()
+	for _, dir := range dirs {
+		envs.Paths.Add(dir)
+	}
+	return &scopedTool{Name: name, Version: "21.0.2", ConfigScope: scope, Scope: scope, Envs: envs}
+}
+
+func TestResolveWhichAttributesSdkAndShadowedSystemBinary(t *testing.T) {
+	root := t.TempDir()
+	sdkDir := filepath.Join(root, "sdk", "bin")
+	systemDir := filepath.Join(root, "usr", "bin")
+	sdkJava := writeExecutable(t, sdkDir, "java")
+	systemJava := writeExecutable(t, systemDir, "java")
+	tool := newScopedTool("java", env.Project, sdkDir)
+
+	result, ok := resolveWhich("java", []string{sdkDir, systemDir}, []*scopedTool{tool})
+	if !ok {
+		t.Fatal("expected java to be found")
+	}
+	if result.Path != sdkJava || result.Tool != tool {
+		t.Fatalf("expected java from SDK, got path %q tool %+v", result.Path, result.Tool)
+	}
+	if len(result.Shadowed) != 1 || result.Shadowed[0] != systemJava {
+		t.Fatalf("expected system java to be shadowed, got %v", result.Shadowed)
+	}
+}
+
+func TestResolveWhichReportsSystemBinaryHidingSdk(t *testing.T) {
+	root := t.TempDir()
+	venvDir := filepath.Join(root, "venv", "bin")
+	sdkDir := filepath.Join(root, "sdk", "bin")
+	venvPython := writeExecutable(t, venvDir, "python")
+	writeExecutable(t, sdkDir, "python")
+	tool := newScopedTool("python", env.Global, sdkDir)
+
+	result, ok := resolveWhich("python", []string{venvDir, sdkDir}, []*scopedTool{tool})
+	if !ok {
+		t.Fatal("expected python to be found")
+	}
+	if result.Path != venvPython || result.Tool != nil {
+		t.Fatalf("expected system python, got path %q tool %+v", result.Path, result.Tool)
+	}
+	if result.HiddenTool != tool {
+		t.Fatalf("expected SDK python to be reported as hidden, got %+v", result.HiddenTool)
+	}
+}
+
+func TestResolveWhichIgnoresNonExecutableFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes"), []byte("text"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, ok := resolveWhich("notes", []string{dir}, nil); ok {
+		t.Fatal("expected non-executable file to be ignored")
+	}
+}
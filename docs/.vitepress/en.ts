@@ -80,8 +80,15 @@ function sidebar(): DefaultTheme.Sidebar {
                         {text: 'http', link: '/plugins/library/http'},
                         {text: 'html', link: '/plugins/library/html'},
                         {text: 'json', link: '/plugins/library/json'},
+                        {text: 'yaml', link: '/plugins/library/yaml'},
+                        {text: 'toml', link: '/plugins/library/toml'},
+                        {text: 'xml', link: '/plugins/library/xml'},
                         {text: 'strings', link: '/plugins/library/strings'},
                         {text: 'archiver', link: '/plugins/library/archiver'},
+                        {text: 'semver', link: '/plugins/library/semver'},
+                        {text: 'cmd', link: '/plugins/library/cmd'},
+                        {text: 'file', link: '/plugins/library/file'},
+                        {text: 'log', link: '/plugins/library/log'},
                     ]
                 },
 
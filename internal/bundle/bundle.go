@@ -0,0 +1,254 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package bundle packs plugins and installed runtimes into a single archive,
+// so that they can be moved to machines without network access.
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/version-fox/vfox/internal/shared/util"
+)
+
+const (
+	ManifestFilename = "manifest.json"
+	FormatVersion    = 1
+
+	pluginsDir  = "plugins"
+	installsDir = "installs"
+)
+
+// Entry is a directory packed into a bundle.
+type Entry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Path is the location of the entry inside the bundle, using forward slashes.
+	Path string `json:"path"`
+	// Checksum is the digest of the directory tree, see Checksum.
+	Checksum string `json:"checksum"`
+	// Dir is the directory on disk, the source when creating and the
+	// extracted location when importing.
+	Dir string `json:"-"`
+}
+
+// Manifest describes the content of a bundle.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	VfoxVersion   string    `json:"vfox_version"`
+	OS            string    `json:"os"`
+	Arch          string    `json:"arch"`
+	Plugins       []*Entry  `json:"plugins"`
+	Runtimes      []*Entry  `json:"runtimes"`
+}
+
+// NewManifest creates an empty manifest for the current platform.
+func NewManifest(vfoxVersion string) *Manifest {
+	return &Manifest{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		VfoxVersion:   vfoxVersion,
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		Plugins:       []*Entry{},
+		Runtimes:      []*Entry{},
+	}
+}
+
+// AddPlugin adds the plugin installed in dir.
+func (m *Manifest) AddPlugin(name, version, dir string) {
+	m.Plugins = append(m.Plugins, &Entry{
+		Name:    name,
+		Version: version,
+		Path:    path.Join(pluginsDir, name),
+		Dir:     dir,
+	})
+}
+
+// AddRuntime adds the runtime of the SDK name installed in dir. The base name of
+// dir is kept, so that the runtime is found at the same place after import.
+func (m *Manifest) AddRuntime(name, version, dir string) {
+	m.Runtimes = append(m.Runtimes, &Entry{
+		Name:    name,
+		Version: version,
+		Path:    path.Join(installsDir, name, filepath.Base(dir)),
+		Dir:     dir,
+	})
+}
+
+func (m *Manifest) entries() []*Entry {
+	return append(append([]*Entry{}, m.Plugins...), m.Runtimes...)
+}
+
+// Create computes the checksums of the entries and writes the bundle to dest.
+func Create(dest string, manifest *Manifest) error {
+	entries := manifest.entries()
+	if len(entries) == 0 {
+		return fmt.Errorf("nothing to bundle")
+	}
+	for _, entry := range entries {
+		checksum, err := Checksum(entry.Dir)
+		if err != nil {
+			return fmt.Errorf("failed to checksum %s: %w", entry.Dir, err)
+		}
+		entry.Checksum = checksum
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	compressor, err := util.NewZstdTarCompressor(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	// The manifest sits next to the plugins and installs folders, so the decompressor
+	// never mistakes one of them for a single root folder to strip.
+	if err = compressor.AddFile(ManifestFilename, content, 0644); err != nil {
+		_ = compressor.Close()
+		return err
+	}
+	for _, entry := range entries {
+		if err = compressor.AddDir(entry.Dir, entry.Path); err != nil {
+			_ = compressor.Close()
+			return fmt.Errorf("failed to add %s: %w", entry.Dir, err)
+		}
+	}
+	return compressor.Close()
+}
+
+// Extract unpacks the bundle src into dest and verifies its content against the manifest.
+// The Dir of every entry of the returned manifest points into dest.
+func Extract(src, dest string) (*Manifest, error) {
+	decompressor := util.NewDecompressor(src)
+	if decompressor == nil {
+		return nil, fmt.Errorf("unsupported bundle format: %s", filepath.Base(src))
+	}
+	if err := decompressor.Decompress(dest); err != nil {
+		return nil, fmt.Errorf("failed to unpack bundle: %w", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dest, ManifestFilename))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle, %s not found: %w", ManifestFilename, err)
+	}
+	manifest := &Manifest{}
+	if err = json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("bundle format %d is not supported, please upgrade vfox", manifest.FormatVersion)
+	}
+	if manifest.OS != runtime.GOOS || manifest.Arch != runtime.GOARCH {
+		return nil, fmt.Errorf("bundle was created for %s/%s and cannot be used on %s/%s",
+			manifest.OS, manifest.Arch, runtime.GOOS, runtime.GOARCH)
+	}
+
+	for _, entry := range manifest.entries() {
+		if !isValidName(entry.Name) || !isValidName(entry.Version) || !isValidEntryPath(entry) {
+			return nil, fmt.Errorf("invalid bundle entry %s", entry.Path)
+		}
+		dir := filepath.Join(dest, filepath.FromSlash(entry.Path))
+		checksum, err := Checksum(dir)
+		if err != nil {
+			return nil, fmt.Errorf("bundle entry %s is missing: %w", entry.Path, err)
+		}
+		if checksum != entry.Checksum {
+			return nil, fmt.Errorf("checksum mismatch for %s@%s, the bundle may be corrupted", entry.Name, entry.Version)
+		}
+		entry.Dir = dir
+	}
+	return manifest, nil
+}
+
+// isValidName reports whether s can be used as a single path element.
+func isValidName(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, `/\:`)
+}
+
+// isValidEntryPath reports whether the entry is stored where AddPlugin or AddRuntime put it.
+func isValidEntryPath(entry *Entry) bool {
+	parts := strings.Split(entry.Path, "/")
+	switch {
+	case len(parts) == 2 && parts[0] == pluginsDir:
+		return parts[1] == entry.Name
+	case len(parts) == 3 && parts[0] == installsDir:
+		return parts[1] == entry.Name && isValidName(parts[2])
+	default:
+		return false
+	}
+}
+
+// Checksum returns the sha256 digest of the directory tree dir, covering the relative
+// path, type and content of every file and the target of every symlink.
+func Checksum(dir string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hash, "l %s %s\n", rel, filepath.ToSlash(link))
+		case d.IsDir():
+			fmt.Fprintf(hash, "d %s\n", rel)
+		case d.Type().IsRegular():
+			sum, err := fileChecksum(p)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hash, "f %s %s\n", rel, sum)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func fileChecksum(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
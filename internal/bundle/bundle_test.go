@@ -0,0 +1,139 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/version-fox/vfox/internal/shared/util"
+)
+
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func newTestManifest(t *testing.T) *Manifest {
+	t.Helper()
+	root := t.TempDir()
+	pluginDir := filepath.Join(root, "plugin", "nodejs")
+	writeFile(t, filepath.Join(pluginDir, "metadata.lua"), "PLUGIN = {}", 0644)
+	runtimeDir := filepath.Join(root, "cache", "nodejs", "v-20.1.0")
+	writeFile(t, filepath.Join(runtimeDir, "nodejs-20.1.0", "bin", "node"), "#!/bin/sh\n", 0755)
+
+	manifest := NewManifest("1.0.0")
+	manifest.AddPlugin("nodejs", "0.1.0", pluginDir)
+	manifest.AddRuntime("nodejs", "20.1.0", runtimeDir)
+	return manifest
+}
+
+func TestCreateAndExtract(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "toolchain.tar.zst")
+	if err := Create(dest, newTestManifest(t)); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	extractDir := t.TempDir()
+	manifest, err := Extract(dest, extractDir)
+	if err != nil {
+		t.Fatalf("Extract() failed: %v", err)
+	}
+	if len(manifest.Plugins) != 1 || len(manifest.Runtimes) != 1 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	rt := manifest.Runtimes[0]
+	if rt.Dir != filepath.Join(extractDir, "installs", "nodejs", "v-20.1.0") {
+		t.Fatalf("unexpected runtime dir: %s", rt.Dir)
+	}
+	node := filepath.Join(rt.Dir, "nodejs-20.1.0", "bin", "node")
+	info, err := os.Stat(node)
+	if err != nil {
+		t.Fatalf("expected runtime file to be extracted: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+		t.Fatalf("expected executable mode to be kept, got %v", info.Mode())
+	}
+	if _, err := os.Stat(filepath.Join(manifest.Plugins[0].Dir, "metadata.lua")); err != nil {
+		t.Fatalf("expected plugin to be extracted: %v", err)
+	}
+}
+
+func TestExtractDetectsTampering(t *testing.T) {
+	manifest := newTestManifest(t)
+	for _, entry := range manifest.entries() {
+		checksum, err := Checksum(entry.Dir)
+		if err != nil {
+			t.Fatalf("Checksum() failed: %v", err)
+		}
+		entry.Checksum = checksum
+	}
+	// The runtime content no longer matches the recorded checksum
+	writeFile(t, filepath.Join(manifest.Runtimes[0].Dir, "nodejs-20.1.0", "bin", "node"), "#!/bin/sh\necho changed\n", 0755)
+
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("failed to marshal manifest: %v", err)
+	}
+	dest := filepath.Join(t.TempDir(), "tampered.tar.zst")
+	compressor, err := util.NewZstdTarCompressor(dest)
+	if err != nil {
+		t.Fatalf("NewZstdTarCompressor() failed: %v", err)
+	}
+	if err = compressor.AddFile(ManifestFilename, content, 0644); err != nil {
+		t.Fatalf("AddFile() failed: %v", err)
+	}
+	for _, entry := range manifest.entries() {
+		if err = compressor.AddDir(entry.Dir, entry.Path); err != nil {
+			t.Fatalf("AddDir() failed: %v", err)
+		}
+	}
+	if err = compressor.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if _, err := Extract(dest, t.TempDir()); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got: %v", err)
+	}
+}
+
+func TestIsValidEntryPath(t *testing.T) {
+	tests := []struct {
+		entry *Entry
+		want  bool
+	}{
+		{&Entry{Name: "nodejs", Path: "plugins/nodejs"}, true},
+		{&Entry{Name: "nodejs", Path: "installs/nodejs/v-20.1.0"}, true},
+		{&Entry{Name: "nodejs", Path: "plugins/java"}, false},
+		{&Entry{Name: "nodejs", Path: "installs/nodejs/../../etc"}, false},
+		{&Entry{Name: "nodejs", Path: "../plugins/nodejs"}, false},
+	}
+	for _, tt := range tests {
+		if got := isValidEntryPath(tt.entry); got != tt.want {
+			t.Errorf("isValidEntryPath(%q) = %v, want %v", tt.entry.Path, got, tt.want)
+		}
+	}
+}
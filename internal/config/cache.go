@@ -22,6 +22,9 @@ import (
 	"github.com/version-fox/vfox/internal/shared/cache"
 )
 
+// DefaultHttpCacheSize is how many MiB of HTTP responses to plugins are cached, unless configured.
+const DefaultHttpCacheSize = 256
+
 var (
 	EmptyCache = &Cache{
 		AvailableHookDuration: cache.Duration(12 * time.Hour),
@@ -31,4 +34,18 @@ var (
 // Cache is the cache configuration
 type Cache struct {
 	AvailableHookDuration cache.Duration `yaml:"availableHookDuration"` // Available hook result cache time
+	// HttpCacheSize is how many MiB of HTTP responses to plugins are cached,
+	// 0 for DefaultHttpCacheSize and -1 to disable the cache.
+	HttpCacheSize int64 `yaml:"httpCacheSize,omitempty"`
+}
+
+// HttpCacheBytes returns the size of the HTTP cache in bytes, 0 if disabled.
+func (c *Cache) HttpCacheBytes() int64 {
+	switch {
+	case c.HttpCacheSize < 0:
+		return 0
+	case c.HttpCacheSize == 0:
+		return DefaultHttpCacheSize << 20
+	}
+	return c.HttpCacheSize << 20
 }
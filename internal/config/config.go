@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/version-fox/vfox/internal/shared/cache"
 	"github.com/version-fox/vfox/internal/shared/util"
 	"gopkg.in/yaml.v3"
 )
@@ -32,6 +34,8 @@ type Config struct {
 	LegacyVersionFile *LegacyVersionFile `yaml:"legacyVersionFile"`
 	Cache             *Cache             `yaml:"cache"`
 	Gitignore         *Gitignore         `yaml:"gitignore"`
+	Upgrade           *Upgrade           `yaml:"upgrade"`
+	Plugin            *Plugin            `yaml:"plugin"`
 }
 
 const filename = "config.yaml"
@@ -44,6 +48,8 @@ var (
 		LegacyVersionFile: EmptyLegacyVersionFile,
 		Cache:             EmptyCache,
 		Gitignore:         EmptyGitignore,
+		Upgrade:           EmptyUpgrade,
+		Plugin:            EmptyPlugin,
 	}
 )
 
@@ -83,6 +89,12 @@ func NewConfigWithPath(p string) (*Config, error) {
 	if config.Gitignore == nil {
 		config.Gitignore = EmptyGitignore
 	}
+	if config.Upgrade == nil {
+		config.Upgrade = EmptyUpgrade
+	}
+	if config.Plugin == nil {
+		config.Plugin = EmptyPlugin
+	}
 	return config, nil
 
 }
@@ -136,6 +148,12 @@ func Merge(sharedConfig, userConfig *Config) *Config {
 	// Merge Gitignore: user overrides shared
 	result.Gitignore = mergeGitignore(sharedConfig.Gitignore, userConfig.Gitignore)
 
+	// Merge Upgrade: user overrides shared, field by field
+	result.Upgrade = mergeUpgrade(sharedConfig.Upgrade, userConfig.Upgrade)
+
+	// Merge Plugin: user overrides shared, hook by hook
+	result.Plugin = mergePlugin(sharedConfig.Plugin, userConfig.Plugin)
+
 	// Apply defaults to any remaining nil fields
 	return ensureDefaults(result)
 }
@@ -163,6 +181,12 @@ func ensureDefaults(c *Config) *Config {
 	if c.Gitignore == nil {
 		c.Gitignore = EmptyGitignore
 	}
+	if c.Upgrade == nil {
+		c.Upgrade = EmptyUpgrade
+	}
+	if c.Plugin == nil {
+		c.Plugin = EmptyPlugin
+	}
 	return c
 }
 
@@ -221,15 +245,18 @@ func mergeCache(shared, user *Cache) *Cache {
 		}
 		return EmptyCache
 	}
-	// User config exists, check if it's the default EmptyCache
-	if shared != nil && user.AvailableHookDuration == EmptyCache.AvailableHookDuration {
-		// User has default cache duration, but shared has something different, use shared
-		if shared.AvailableHookDuration != EmptyCache.AvailableHookDuration {
-			return shared
-		}
+	if shared == nil {
+		return user
+	}
+	result := *user
+	// User has the default cache duration, but shared has something different, use shared
+	if user.AvailableHookDuration == EmptyCache.AvailableHookDuration {
+		result.AvailableHookDuration = shared.AvailableHookDuration
 	}
-	// User has a non-default cache setting, or both are default
-	return user
+	if user.HttpCacheSize == 0 {
+		result.HttpCacheSize = shared.HttpCacheSize
+	}
+	return &result
 }
 
 // mergeGitignore merges gitignore configs with user taking precedence.
@@ -245,6 +272,60 @@ func mergeGitignore(shared, user *Gitignore) *Gitignore {
 	return EmptyGitignore
 }
 
+// mergeUpgrade merges upgrade configs with user taking precedence.
+// The default channel is considered "unset", so a shared channel applies unless the user picks another one.
+func mergeUpgrade(shared, user *Upgrade) *Upgrade {
+	if shared == nil {
+		if user != nil {
+			return user
+		}
+		return EmptyUpgrade
+	}
+	if user == nil {
+		return shared
+	}
+	result := *shared
+	if user.ManifestUrl != "" {
+		result.ManifestUrl = user.ManifestUrl
+	}
+	if user.Channel != "" && user.Channel != EmptyUpgrade.Channel {
+		result.Channel = user.Channel
+	}
+	return &result
+}
+
+// mergePlugin merges plugin configs, the timeouts of the user replacing those of the same hooks
+func mergePlugin(shared, user *Plugin) *Plugin {
+	if shared == nil {
+		if user != nil {
+			return user
+		}
+		return EmptyPlugin
+	}
+	if user == nil {
+		return shared
+	}
+	result := &Plugin{
+		HookTimeouts: make(map[string]cache.Duration, len(shared.HookTimeouts)+len(user.HookTimeouts)),
+		MemoryLimit:  shared.MemoryLimit,
+	}
+	for name, timeout := range shared.HookTimeouts {
+		result.HookTimeouts[name] = timeout
+	}
+	for name, timeout := range user.HookTimeouts {
+		for existing := range result.HookTimeouts {
+			if strings.EqualFold(existing, name) {
+				delete(result.HookTimeouts, existing)
+			}
+		}
+		result.HookTimeouts[name] = timeout
+	}
+	if user.MemoryLimit != 0 {
+		result.MemoryLimit = user.MemoryLimit
+	}
+	return result
+}
+
 // Helper functions to check if config is empty
 // A config is considered empty if it's nil or all fields are at default/zero values
 func isProxyEmpty(p *Proxy) bool {
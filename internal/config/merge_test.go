@@ -358,3 +358,73 @@ func TestIsEmptyHelpers(t *testing.T) {
 		}
 	})
 }
+
+func TestMergeUpgrade(t *testing.T) {
+	tests := []struct {
+		name   string
+		shared *Upgrade
+		user   *Upgrade
+		want   Upgrade
+	}{
+		{"both nil", nil, nil, *EmptyUpgrade},
+		{"only user", nil, &Upgrade{Channel: "beta"}, Upgrade{Channel: "beta"}},
+		{"default user inherits shared", &Upgrade{ManifestUrl: "https://internal/manifest.json", Channel: "beta"}, EmptyUpgrade,
+			Upgrade{ManifestUrl: "https://internal/manifest.json", Channel: "beta"}},
+		{"user overrides fields", &Upgrade{ManifestUrl: "https://internal/manifest.json", Channel: "stable"}, &Upgrade{Channel: "beta"},
+			Upgrade{ManifestUrl: "https://internal/manifest.json", Channel: "beta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeUpgrade(tt.shared, tt.user); *got != tt.want {
+				t.Errorf("mergeUpgrade() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePlugin(t *testing.T) {
+	shared := &Plugin{
+		HookTimeouts: map[string]cache.Duration{"available": cache.Duration(2 * time.Minute), "envKeys": cache.Duration(time.Second)},
+		MemoryLimit:  256,
+	}
+	user := &Plugin{HookTimeouts: map[string]cache.Duration{"EnvKeys": cache.Duration(3 * time.Second)}}
+	got := mergePlugin(shared, user)
+	if got.MemoryLimit != 256 {
+		t.Errorf("expected memory limit of shared config, got %d", got.MemoryLimit)
+	}
+	if timeout := got.HookTimeout("Available"); timeout != 2*time.Minute {
+		t.Errorf("expected available timeout of shared config, got %s", timeout)
+	}
+	if timeout := got.HookTimeout("envKeys"); timeout != 3*time.Second {
+		t.Errorf("expected envKeys timeout of user config, got %s", timeout)
+	}
+	if timeout := got.HookTimeout("PreUse"); timeout != time.Duration(DefaultHookTimeouts["preUse"]) {
+		t.Errorf("expected default preUse timeout, got %s", timeout)
+	}
+	if timeout := got.HookTimeout("PostInstall"); timeout != 0 {
+		t.Errorf("expected no postInstall timeout, got %s", timeout)
+	}
+}
+
+func TestMergeCache(t *testing.T) {
+	tests := []struct {
+		name   string
+		shared *Cache
+		user   *Cache
+		want   Cache
+	}{
+		{"both nil", nil, nil, *EmptyCache},
+		{"default user inherits shared", &Cache{AvailableHookDuration: cache.Duration(time.Hour), HttpCacheSize: 64}, EmptyCache,
+			Cache{AvailableHookDuration: cache.Duration(time.Hour), HttpCacheSize: 64}},
+		{"user overrides fields", &Cache{AvailableHookDuration: cache.Duration(time.Hour), HttpCacheSize: 64},
+			&Cache{AvailableHookDuration: EmptyCache.AvailableHookDuration, HttpCacheSize: -1},
+			Cache{AvailableHookDuration: cache.Duration(time.Hour), HttpCacheSize: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeCache(tt.shared, tt.user); *got != tt.want {
+				t.Errorf("mergeCache() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/version-fox/vfox/internal/shared/cache"
+)
+
+// DefaultHookTimeouts are the timeouts of the hooks not set in Plugin.HookTimeouts. The hooks run by
+// `vfox activate` and `vfox env` are kept short, as they hold up the shell prompt, and PostInstall,
+// which may build from source, has none.
+var DefaultHookTimeouts = map[string]cache.Duration{
+	"available":       cache.Duration(time.Minute),
+	"preInstall":      cache.Duration(time.Minute),
+	"postInstall":     0,
+	"envKeys":         cache.Duration(10 * time.Second),
+	"preUse":          cache.Duration(10 * time.Second),
+	"parseLegacyFile": cache.Duration(10 * time.Second),
+	"preUninstall":    cache.Duration(time.Minute),
+}
+
+// Plugin limits the execution of plugin hooks.
+type Plugin struct {
+	// HookTimeouts overrides DefaultHookTimeouts by hook name, such as available or envKeys.
+	// A timeout of 0 lets the hook run forever.
+	HookTimeouts map[string]cache.Duration `yaml:"hookTimeouts"`
+	// MemoryLimit is how many MiB a hook may allocate, 0 for no limit.
+	MemoryLimit int64 `yaml:"memoryLimit"`
+}
+
+var EmptyPlugin = &Plugin{
+	HookTimeouts: map[string]cache.Duration{},
+	MemoryLimit:  0,
+}
+
+// HookTimeout returns the timeout of hook, matching its name case-insensitively, or 0 for none.
+func (p *Plugin) HookTimeout(hook string) time.Duration {
+	for _, timeouts := range []map[string]cache.Duration{p.HookTimeouts, DefaultHookTimeouts} {
+		for name, timeout := range timeouts {
+			if strings.EqualFold(name, hook) {
+				return max(time.Duration(timeout), 0)
+			}
+		}
+	}
+	return 0
+}
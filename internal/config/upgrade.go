@@ -0,0 +1,29 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package config
+
+// Upgrade configures where `vfox upgrade` looks for new releases.
+type Upgrade struct {
+	// ManifestUrl is the release manifest to upgrade from, GitHub releases are used if empty.
+	ManifestUrl string `yaml:"manifestUrl"`
+	Channel     string `yaml:"channel"` // stable or beta
+}
+
+var EmptyUpgrade = &Upgrade{
+	ManifestUrl: "",
+	Channel:     "stable",
+}
@@ -19,15 +19,18 @@
 package env
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/version-fox/vfox/internal/config"
 	"github.com/version-fox/vfox/internal/pathmeta"
+	"github.com/version-fox/vfox/internal/shared/httpcache"
 	"github.com/version-fox/vfox/internal/shared/logger"
 )
 
@@ -37,6 +40,12 @@ type RuntimeEnvContext struct {
 	CurrentWorkingDir string             // CurrentWorkingDir is the current working directory.
 	PathMeta          *pathmeta.PathMeta // PathMeta holds the path info of the environment.
 	RuntimeVersion    string             // RuntimeVersion is the version of vfox
+	// HttpTransport, if set, carries the HTTP requests of plugins instead of the network.
+	HttpTransport http.RoundTripper
+	// Context, if set, cancels the running hooks of plugins when done.
+	Context context.Context
+	// ShellOutput is set when the shell evaluates the output of vfox, so that plugins print to stderr.
+	ShellOutput bool
 }
 
 // LoadVfoxTomlByScope loads the config for the specified scope
@@ -77,21 +86,41 @@ func (m *RuntimeEnvContext) LoadVfoxTomlChainByScopes(scopes ...UseScope) (VfoxT
 
 // HttpClient creates an HTTP client based on the proxy settings in the user configuration.
 func (m *RuntimeEnvContext) HttpClient() *http.Client {
-	var client *http.Client
-	if m.UserConfig.Proxy.Enable {
-		if uri, err := url.Parse(m.UserConfig.Proxy.Url); err == nil {
-			transPort := &http.Transport{
-				Proxy: http.ProxyURL(uri),
-			}
-			client = &http.Client{
-				Transport: transPort,
-			}
-		}
-	} else {
-		client = http.DefaultClient
+	return &http.Client{Transport: sharedTransport(m.UserConfig.Proxy)}
+}
+
+// PluginTransport returns the transport of the HTTP requests of plugins, HttpTransport if set.
+// Otherwise responses are cached in PathMeta.User.HttpCache, unless the cache is disabled.
+func (m *RuntimeEnvContext) PluginTransport() http.RoundTripper {
+	if m.HttpTransport != nil {
+		return m.HttpTransport
+	}
+	transport := sharedTransport(m.UserConfig.Proxy)
+	if size := m.UserConfig.Cache.HttpCacheBytes(); size > 0 && m.PathMeta != nil && m.PathMeta.User.HttpCache != "" {
+		return httpcache.New(m.PathMeta.User.HttpCache, size, transport)
 	}
+	return transport
+}
+
+// transports holds a transport per proxy URL, so that vfox and its plugins reuse connections.
+var transports sync.Map
 
-	return client
+func sharedTransport(proxy *config.Proxy) *http.Transport {
+	proxyUrl := ""
+	if proxy != nil && proxy.Enable {
+		proxyUrl = proxy.Url
+	}
+	if t, ok := transports.Load(proxyUrl); ok {
+		return t.(*http.Transport)
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyUrl != "" {
+		if uri, err := url.Parse(proxyUrl); err == nil {
+			t.Proxy = http.ProxyURL(uri)
+		}
+	}
+	actual, _ := transports.LoadOrStore(proxyUrl, t)
+	return actual.(*http.Transport)
 }
 
 // GetLinkDirPathByScope returns the symlink directory path for the given scope.
@@ -0,0 +1,183 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package env
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Format is a file format the resolved envs can be rendered in,
+// for tools that do not go through a shell.
+type Format string
+
+const (
+	// FormatDotenv renders KEY="value" lines, as read by docker compose and most dotenv loaders.
+	FormatDotenv Format = "dotenv"
+	// FormatGithub appends to the files referenced by $GITHUB_ENV and $GITHUB_PATH.
+	FormatGithub Format = "github"
+	// FormatSystemd renders a [Service] drop-in with Environment= lines.
+	FormatSystemd Format = "systemd"
+	// FormatDockerfile renders ENV instructions.
+	FormatDockerfile Format = "dockerfile"
+)
+
+const (
+	GithubEnvFlag  = "GITHUB_ENV"
+	GithubPathFlag = "GITHUB_PATH"
+)
+
+// Formats lists all supported formats.
+var Formats = []Format{FormatDotenv, FormatGithub, FormatSystemd, FormatDockerfile}
+
+// Render renders the vfox managed envs in the given format. systemPaths are appended
+// after the vfox paths for formats which cannot reference the PATH they are applied to.
+// Unset variables (nil values) are skipped, as none of the formats can express them.
+func Render(format Format, envs *Envs, systemPaths *Paths) (string, error) {
+	switch format {
+	case FormatDotenv:
+		return renderLines(envs, fullPath(envs, systemPaths), func(key, value string) string {
+			return fmt.Sprintf("%s=\"%s\"\n", key, escapeDoubleQuoted(value))
+		}), nil
+	case FormatSystemd:
+		body := renderLines(envs, fullPath(envs, systemPaths), func(key, value string) string {
+			return fmt.Sprintf("Environment=\"%s=%s\"\n", key, escapeSystemd(value))
+		})
+		return "[Service]\n" + body, nil
+	case FormatDockerfile:
+		body := renderLines(envs, "", func(key, value string) string {
+			return fmt.Sprintf("ENV %s=\"%s\"\n", key, escapeDoubleQuoted(value))
+		})
+		if len(envs.Paths.Slice()) > 0 {
+			// Prepend to the PATH of the image instead of replacing it
+			body += fmt.Sprintf("ENV %s=\"%s%c${%s}\"\n", PathVarName,
+				escapeDoubleQuoted(joinPaths(envs.Paths)), os.PathListSeparator, PathVarName)
+		}
+		return body, nil
+	case FormatGithub:
+		return renderGithubEnv(envs), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// WriteGithubEnv appends the variables to $GITHUB_ENV and the vfox paths to $GITHUB_PATH,
+// so that they are available to the following steps of a GitHub Actions job.
+func WriteGithubEnv(envs *Envs) error {
+	envFile := os.Getenv(GithubEnvFlag)
+	pathFile := os.Getenv(GithubPathFlag)
+	if envFile == "" || pathFile == "" {
+		return fmt.Errorf("%s and %s are not set, the github format only works in GitHub Actions", GithubEnvFlag, GithubPathFlag)
+	}
+	if err := appendToFile(envFile, renderGithubEnv(envs)); err != nil {
+		return err
+	}
+	var paths strings.Builder
+	for _, p := range envs.Paths.Slice() {
+		paths.WriteString(p)
+		paths.WriteString("\n")
+	}
+	return appendToFile(pathFile, paths.String())
+}
+
+// renderGithubEnv renders the variables with the heredoc syntax of $GITHUB_ENV, which
+// supports multiline values. PATH is left out, it goes to $GITHUB_PATH.
+func renderGithubEnv(envs *Envs) string {
+	var b strings.Builder
+	for _, key := range sortedKeys(envs.Variables) {
+		value := envs.Variables[key]
+		if value == nil || key == PathVarName {
+			continue
+		}
+		delimiter := githubDelimiter()
+		fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", key, delimiter, *value, delimiter)
+	}
+	return b.String()
+}
+
+func githubDelimiter() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "VFOX_EOF_" + hex.EncodeToString(buf)
+}
+
+func appendToFile(path, content string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	if _, err = f.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// renderLines renders the variables in key order followed by PATH, if path is not empty.
+func renderLines(envs *Envs, path string, line func(key, value string) string) string {
+	var b strings.Builder
+	for _, key := range sortedKeys(envs.Variables) {
+		value := envs.Variables[key]
+		if value == nil || key == PathVarName {
+			continue
+		}
+		b.WriteString(line(key, *value))
+	}
+	if path != "" {
+		b.WriteString(line(PathVarName, path))
+	}
+	return b.String()
+}
+
+func fullPath(envs *Envs, systemPaths *Paths) string {
+	if len(envs.Paths.Slice()) == 0 {
+		return ""
+	}
+	paths := NewPaths(EmptyPaths)
+	paths.Merge(envs.Paths)
+	paths.Merge(systemPaths)
+	return joinPaths(paths)
+}
+
+// joinPaths joins the paths with the separator of the OS, unlike Paths.String
+// which adapts it to the shell vfox is hooked into.
+func joinPaths(paths *Paths) string {
+	return strings.Join(paths.Slice(), string(os.PathListSeparator))
+}
+
+func sortedKeys(vars Vars) []string {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapeDoubleQuoted escapes a value for a double-quoted string in which $ is expanded.
+func escapeDoubleQuoted(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`).Replace(value)
+}
+
+// escapeSystemd escapes a value for a quoted Environment= assignment, where % starts a specifier.
+func escapeSystemd(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "%", "%%").Replace(value)
+}
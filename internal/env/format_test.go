@@ -0,0 +1,117 @@
+//go:build !windows
+
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newFormatTestEnvs() *Envs {
+	javaHome := "/opt/java 21"
+	opts := `-Dname="vfox" -Dprice=$5 100%`
+	envs := NewEnvs()
+	envs.Variables["JAVA_HOME"] = &javaHome
+	envs.Variables["JAVA_OPTS"] = &opts
+	envs.Variables["REMOVED"] = nil
+	envs.Paths.Add("/opt/java 21/bin")
+	return envs
+}
+
+func TestRenderDotenv(t *testing.T) {
+	system := NewPaths(EmptyPaths)
+	system.Add("/usr/bin")
+
+	got, err := Render(FormatDotenv, newFormatTestEnvs(), system)
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	want := `JAVA_HOME="/opt/java 21"
+JAVA_OPTS="-Dname=\"vfox\" -Dprice=\$5 100%"
+PATH="/opt/java 21/bin:/usr/bin"
+`
+	if got != want {
+		t.Fatalf("unexpected dotenv output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderSystemd(t *testing.T) {
+	got, err := Render(FormatSystemd, newFormatTestEnvs(), NewPaths(EmptyPaths))
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	want := `[Service]
+Environment="JAVA_HOME=/opt/java 21"
+Environment="JAVA_OPTS=-Dname=\"vfox\" -Dprice=$5 100%%"
+Environment="PATH=/opt/java 21/bin"
+`
+	if got != want {
+		t.Fatalf("unexpected systemd output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderDockerfileKeepsImagePath(t *testing.T) {
+	got, err := Render(FormatDockerfile, newFormatTestEnvs(), nil)
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !strings.HasSuffix(got, `ENV PATH="/opt/java 21/bin:${PATH}"`+"\n") {
+		t.Fatalf("expected PATH to be prepended to the image PATH, got:\n%s", got)
+	}
+	if strings.Contains(got, "REMOVED") {
+		t.Fatalf("unset variables should be skipped, got:\n%s", got)
+	}
+}
+
+func TestWriteGithubEnv(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "env")
+	pathFile := filepath.Join(dir, "path")
+	t.Setenv(GithubEnvFlag, envFile)
+	t.Setenv(GithubPathFlag, pathFile)
+
+	if err := WriteGithubEnv(newFormatTestEnvs()); err != nil {
+		t.Fatalf("WriteGithubEnv() failed: %v", err)
+	}
+
+	envContent, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatalf("failed to read env file: %v", err)
+	}
+	if !strings.HasPrefix(string(envContent), "JAVA_HOME<<VFOX_EOF_") || !strings.Contains(string(envContent), "\n/opt/java 21\nVFOX_EOF_") {
+		t.Fatalf("unexpected GITHUB_ENV content:\n%s", envContent)
+	}
+	pathContent, err := os.ReadFile(pathFile)
+	if err != nil {
+		t.Fatalf("failed to read path file: %v", err)
+	}
+	if string(pathContent) != "/opt/java 21/bin\n" {
+		t.Fatalf("unexpected GITHUB_PATH content: %q", pathContent)
+	}
+}
+
+func TestWriteGithubEnvOutsideActions(t *testing.T) {
+	t.Setenv(GithubEnvFlag, "")
+	t.Setenv(GithubPathFlag, "")
+	if err := WriteGithubEnv(newFormatTestEnvs()); err == nil {
+		t.Fatal("expected an error outside of GitHub Actions")
+	}
+}
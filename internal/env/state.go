@@ -45,6 +45,9 @@ type ConfigState struct {
 	// Cached env output (shell script)
 	CachedOutput string `json:"cached_output,omitempty"`
 
+	// Tools of the cached output, name to resolved version, to record their use
+	CachedTools map[string]string `json:"cached_tools,omitempty"`
+
 	// State file path
 	stateFilePath string
 }
@@ -207,3 +210,17 @@ func getFileModTime(path string) (int64, error) {
 	}
 	return info.ModTime().Unix(), nil
 }
+
+// SetCachedTools sets the tools of the output passed to the next Update
+func (s *ConfigState) SetCachedTools(tools map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.CachedTools = tools
+}
+
+// GetCachedTools returns the tools of the cached env output
+func (s *ConfigState) GetCachedTools() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.CachedTools
+}
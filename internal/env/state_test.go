@@ -333,3 +333,21 @@ func TestConfigState_HasChanged_PathChanged(t *testing.T) {
 	}
 }
 
+
+func TestConfigState_CachedTools(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	state := NewConfigState(stateFile)
+	state.SetCachedTools(map[string]string{"nodejs": "20.1.0"})
+	if err := state.Update(map[UseScope]string{}, "export PATH=/test"); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	loaded := NewConfigState(stateFile)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if got := loaded.GetCachedTools()["nodejs"]; got != "20.1.0" {
+		t.Errorf("expected cached tool version 20.1.0, got %q", got)
+	}
+}
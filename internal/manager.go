@@ -17,6 +17,7 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,11 +26,13 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/pterm/pterm"
@@ -39,7 +42,9 @@ import (
 	"github.com/version-fox/vfox/internal/env"
 	"github.com/version-fox/vfox/internal/pathmeta"
 	"github.com/version-fox/vfox/internal/plugin"
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
 	"github.com/version-fox/vfox/internal/sdk"
+	"github.com/version-fox/vfox/internal/shared/flock"
 	"github.com/version-fox/vfox/internal/shared/logger"
 	"github.com/version-fox/vfox/internal/shared/util"
 )
@@ -69,6 +74,8 @@ type Manager struct {
 	RuntimeEnvContext *env.RuntimeEnvContext // runtime environment context
 	openSdks          map[string]sdk.Sdk
 	mu                sync.RWMutex // protects openSdks map
+	usage             usageTracker
+	usageMu           sync.Mutex // protects usage
 }
 
 // LookupSdk lookup sdk by name
@@ -125,10 +132,11 @@ func (m *Manager) ResolveVersion(sdkName string, version sdk.Version) sdk.Versio
 
 		logger.Debugf("workspace config: %+v\n", workspaceConfig.GetAllTools())
 		if v, ok := workspaceConfig.Tools.GetVersion(sdkName); ok {
-			return sdk.Version(v)
+			version = sdk.Version(v)
 		}
 	}
-	return version
+	// Aliases are expanded before the version reaches any plugin hook
+	return sdk.ExpandAlias(m.RuntimeEnvContext, sdkName, version)
 }
 
 func (m *Manager) LookupSdkWithInstall(name string, autoConfirm bool) (sdk.Sdk, error) {
@@ -165,7 +173,7 @@ func (m *Manager) LookupSdkWithInstall(name string, autoConfirm bool) (sdk.Sdk,
 				return nil, err
 			}
 			logger.Debugf("Adding plugin: %s (downloadUrl=%s)\n", manifest.Name, manifest.DownloadUrl)
-			if err = m.Add(manifest.Name, manifest.DownloadUrl, ""); err != nil {
+			if err = m.Add(manifest.Name, manifest.DownloadUrl, "", autoConfirm); err != nil {
 				return nil, err
 			}
 			return m.LookupSdk(manifest.Name)
@@ -229,6 +237,8 @@ func (m *Manager) LoadAllSdk() ([]sdk.Sdk, error) {
 }
 
 func (m *Manager) Close() {
+	m.flushUsage()
+
 	// Use write lock to ensure no other operations are in progress
 	// while we're closing SDK handlers
 	m.mu.Lock()
@@ -237,6 +247,8 @@ func (m *Manager) Close() {
 	for _, handler := range m.openSdks {
 		handler.Close()
 	}
+	// Allow Close to be called more than once, e.g. before replacing the process
+	m.openSdks = make(map[string]sdk.Sdk)
 }
 
 func (m *Manager) Remove(pluginName string) error {
@@ -248,6 +260,11 @@ func (m *Manager) Remove(pluginName string) error {
 		logger.Debugf("Plugin %s not found: %v\n", pluginName, err)
 		return err
 	}
+	lock, err := m.lockPlugin(source.Metadata().Name)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
 
 	if err = source.Unuse(env.Global); err != nil {
 		logger.Debugf("Failed to unuse plugin %s: %v\n", pluginName, err)
@@ -284,7 +301,9 @@ func (m *Manager) Remove(pluginName string) error {
 	return nil
 }
 
-func (m *Manager) Update(pluginName string) error {
+// Update updates the plugin to its latest version. If the new version declares permissions
+// the installed one did not, the user must approve them, or pass autoConfirm.
+func (m *Manager) Update(pluginName string, autoConfirm bool) error {
 	logger.Debugf("Updating plugin: %s\n", pluginName)
 
 	source, err := m.LookupSdk(pluginName)
@@ -293,6 +312,11 @@ func (m *Manager) Update(pluginName string) error {
 		return fmt.Errorf("%s plugin not installed", pluginName)
 	}
 	sdkMetadata := source.Metadata()
+	lock, err := m.lockPlugin(sdkMetadata.Name)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
 	pterm.Printf("Checking plugin manifest...\n")
 	// Update search priority: updateUrl > registry > manifestUrl
 	pluginMetadata := sdkMetadata.PluginMetadata
@@ -341,6 +365,17 @@ func (m *Manager) Update(pluginName string) error {
 		pterm.Printf("%s is already the latest version\n", pterm.Blue(pluginName))
 		return nil
 	}
+	if tempPlugin.Permissions == nil {
+		logger.Warnf("%s plugin %s declares no permissions and runs unrestricted, which is deprecated\n", pluginName, tempPlugin.Version)
+	}
+	if widened := permission.Widened(pluginMetadata.Permissions, tempPlugin.Permissions); len(widened) > 0 {
+		if !autoConfirm && util.IsNonInteractiveTerminal() {
+			return fmt.Errorf("%s plugin %s requests new permissions, use the -y flag to approve them in non-interactive environments", pluginName, tempPlugin.Version)
+		}
+		if !confirmPermissions(fmt.Sprintf("%s plugin %s requests new permissions:", pluginName, tempPlugin.Version), widened, autoConfirm) {
+			return fmt.Errorf("update %s plugin cancelled, permissions not approved", pluginName)
+		}
+	}
 	success := false
 	backupPath := sdkMetadata.PluginInstalledPath + "-bak"
 	logger.Debugf("Backup %s plugin to %s \n", sdkMetadata.PluginInstalledPath, backupPath)
@@ -389,6 +424,16 @@ func (m *Manager) Update(pluginName string) error {
 	return nil
 }
 
+// lockPlugin takes the cross-process lock of the plugin directory name, so that
+// concurrent add, update and remove of the same plugin are serialized.
+func (m *Manager) lockPlugin(name string) (*flock.Lock, error) {
+	path := filepath.Join(m.RuntimeEnvContext.PathMeta.Shared.Plugins, "."+name+".lock")
+	logger.Debugf("Acquiring lock: %s\n", path)
+	return flock.Acquire(path, flock.DefaultTimeout, func() {
+		pterm.Printf("Waiting for another vfox process to finish with %s plugin...\n", pterm.LightBlue(name))
+	})
+}
+
 // fetchPluginManifest fetch plugin from registry by manifest url
 func (m *Manager) fetchPluginManifest(url string) (*RegistryPluginManifest, error) {
 	logger.Debugf("Fetching plugin manifest from: %s\n", url)
@@ -485,7 +530,10 @@ func (m *Manager) downloadPlugin(downloadUrl string) (string, error) {
 //	vfox add --alias node nodejs
 //	vfox add --source /path/to/plugin.zip
 //	vfox add --source /path/to/plugin.zip --alias node [nodejs]
-func (m *Manager) Add(pluginName, url, alias string) error {
+//
+// The user must approve the permissions the plugin declares, unless autoConfirm is set
+// or the terminal is not interactive, in which case they are only printed.
+func (m *Manager) Add(pluginName, url, alias string, autoConfirm bool) error {
 	logger.Debugf("Adding plugin: name=%s, url=%s, alias=%s\n", pluginName, url, alias)
 
 	// For compatibility with older versions of plugin names <category>/<plugin-name>
@@ -502,6 +550,11 @@ func (m *Manager) Add(pluginName, url, alias string) error {
 	var installPath string
 	// first quick check.
 	if pname != "" {
+		lock, err := m.lockPlugin(pname)
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock()
 		installPath = filepath.Join(m.RuntimeEnvContext.PathMeta.Shared.Plugins, pname)
 		if util.FileExists(installPath) {
 			logger.Debugf("Plugin %s already exists at: %s\n", pname, installPath)
@@ -532,12 +585,24 @@ func (m *Manager) Add(pluginName, url, alias string) error {
 	// check plugin exist again as the plugin may be from custom source without plugin name and alias.
 	if pname == "" {
 		pname = tempPlugin.Name
+		lock, err := m.lockPlugin(pname)
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock()
 		installPath = filepath.Join(m.RuntimeEnvContext.PathMeta.Shared.Plugins, pname)
 		logger.Debugf("No plugin name provided, use %s as plugin name, installPath: %s\n", pname, installPath)
 		if util.FileExists(installPath) {
 			return fmt.Errorf("plugin named %s already exists", pname)
 		}
 	}
+	if !autoConfirm && util.IsNonInteractiveTerminal() {
+		return fmt.Errorf("%s plugin %s requests permissions, use the -y flag to approve them in non-interactive environments", pname, tempPlugin.Version)
+	}
+	title := fmt.Sprintf("%s plugin %s requests the following permissions:", pname, tempPlugin.Version)
+	if !confirmPermissions(title, permission.Describe(tempPlugin.Permissions), autoConfirm) {
+		return fmt.Errorf("add %s plugin cancelled, permissions not approved", pname)
+	}
 	logger.Debugf("Moving plugin from %s to %s\n", tempPlugin.InstalledPath, installPath)
 	if err = util.MovePath(tempPlugin.InstalledPath, installPath); err != nil {
 		logger.Debugf("Failed to move plugin: %v\n", err)
@@ -547,14 +612,9 @@ func (m *Manager) Add(pluginName, url, alias string) error {
 	// set legacy filenames
 	if len(tempPlugin.LegacyFilenames) > 0 {
 		logger.Debugf("Add legacy filenames for %s plugin, %+v \n", pname, tempPlugin.LegacyFilenames)
-		lfr, err := m.loadLegacyFileRecord()
-		if err != nil {
+		if err = m.registerLegacyFilenames(pname); err != nil {
 			return err
 		}
-		lfr.Record[pname] = "true"
-		if err = lfr.Save(); err != nil {
-			return fmt.Errorf("add legacy filenames failed: %w", err)
-		}
 	}
 
 	pterm.Println("Plugin info:")
@@ -570,6 +630,25 @@ func (m *Manager) Add(pluginName, url, alias string) error {
 	return nil
 }
 
+// confirmPermissions prints the permissions requested by a plugin and asks the user to
+// approve them, unless autoConfirm is set.
+func confirmPermissions(title string, lines []string, autoConfirm bool) bool {
+	pterm.Println(title)
+	for _, line := range lines {
+		pterm.Println("  -", pterm.LightYellow(line))
+	}
+	if autoConfirm {
+		return true
+	}
+	result, _ := pterm.DefaultInteractiveConfirm.
+		WithTextStyle(&pterm.ThemeDefault.DefaultText).
+		WithConfirmStyle(&pterm.ThemeDefault.DefaultText).
+		WithRejectStyle(&pterm.ThemeDefault.DefaultText).
+		WithDefaultText("Approve these permissions?").
+		Show()
+	return result
+}
+
 // installPluginToTemp install plugin from path that can be a local or remote file to temp dir.
 // NOTE:
 //
@@ -676,6 +755,9 @@ func (m *Manager) CleanTmp() {
 	}
 	_ = os.WriteFile(cleanFlagPath, []byte(strconv.FormatInt(util.GetBeginOfToday(), 10)), os.ModePerm)
 
+	// Installs interrupted by a crash or kill -9 leave their staging directories behind.
+	sdk.CleanStaleStaging(m.RuntimeEnvContext.PathMeta.Shared.Installs)
+
 	procExists := make(map[string]struct{})
 
 	if procList, err := process.Pids(); err == nil {
@@ -819,12 +901,34 @@ func NewSdkManager() (*Manager, error) {
 			CurrentWorkingDir: currentDir,
 			PathMeta:          meta,
 			RuntimeVersion:    RuntimeVersion,
+			Context:           interruptContext(),
 		},
 		openSdks: make(map[string]sdk.Sdk),
 		// mu is intentionally zero-initialized (Go's zero-value mutex is ready to use)
 	}, nil
 }
 
+// interruptGrace is how long the process may take to return once interrupted, before
+// it exits as it would without a handler.
+const interruptGrace = 3 * time.Second
+
+// interruptContext is cancelled on the first SIGINT or SIGTERM, so that running plugin
+// hooks and their http and cmd calls stop. A second signal kills the process.
+var interruptContext = sync.OnceValue(func() context.Context {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		signal.Stop(sigs)
+		logger.Debugf("Received %s, cancelling running hooks\n", sig)
+		cancel(fmt.Errorf("received %s", sig))
+		time.Sleep(interruptGrace)
+		os.Exit(130)
+	}()
+	return ctx
+})
+
 func getWorkingDirectory() string {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -0,0 +1,189 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/version-fox/vfox/internal/bundle"
+	"github.com/version-fox/vfox/internal/pathmeta"
+	"github.com/version-fox/vfox/internal/plugin"
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
+	"github.com/version-fox/vfox/internal/sdk"
+	"github.com/version-fox/vfox/internal/shared/logger"
+	"github.com/version-fox/vfox/internal/shared/util"
+)
+
+// BundleImportResult reports what ImportBundle did with the entries of a bundle.
+type BundleImportResult struct {
+	Manifest *bundle.Manifest
+	// Imported and Skipped contain "name" for plugins and "name@version" for runtimes.
+	Imported []string
+	Skipped  []string
+}
+
+// CreateBundle packs the plugins and installed runtimes of tools, a map of SDK name
+// to version, into the bundle dest.
+func (m *Manager) CreateBundle(dest string, tools map[string]string) (*bundle.Manifest, error) {
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifest := bundle.NewManifest(m.RuntimeEnvContext.RuntimeVersion)
+	for _, name := range names {
+		source, err := m.LookupSdk(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s not supported, error: %w", name, err)
+		}
+		version := source.ExpandAlias(sdk.Version(tools[name]))
+		metadata := source.Metadata()
+		runtimePackage, err := source.GetRuntimePackage(version)
+		if err != nil {
+			return nil, fmt.Errorf("%s@%s is not installed", name, version)
+		}
+		manifest.AddPlugin(metadata.Name, metadata.PluginMetadata.Version, metadata.PluginInstalledPath)
+		manifest.AddRuntime(metadata.Name, string(version), runtimePackage.PackagePath)
+	}
+
+	logger.Debugf("Creating bundle %s with %d runtimes\n", dest, len(manifest.Runtimes))
+	if err := bundle.Create(dest, manifest); err != nil {
+		_ = os.Remove(dest)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ImportBundle unpacks and verifies the bundle src, then moves its plugins and runtimes
+// into the shared root. Plugins and runtimes that already exist are left untouched. The
+// permissions of every imported plugin are approved like with Add, unless autoConfirm.
+func (m *Manager) ImportBundle(src string, autoConfirm bool) (*BundleImportResult, error) {
+	if err := os.MkdirAll(m.RuntimeEnvContext.PathMeta.User.Temp, pathmeta.ReadWriteAuth); err != nil {
+		return nil, err
+	}
+	tempDir, err := os.MkdirTemp(m.RuntimeEnvContext.PathMeta.User.Temp, "bundle-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	logger.Debugf("Unpacking bundle %s to %s\n", src, tempDir)
+	manifest, err := bundle.Extract(src, tempDir)
+	if err != nil {
+		return nil, err
+	}
+	result := &BundleImportResult{Manifest: manifest}
+
+	for _, entry := range manifest.Plugins {
+		imported, err := m.importBundlePlugin(entry, autoConfirm)
+		if err != nil {
+			return nil, err
+		}
+		if !imported {
+			logger.Debugf("Plugin %s already exists, skipping\n", entry.Name)
+			result.Skipped = append(result.Skipped, entry.Name)
+			continue
+		}
+		source, err := m.LookupSdk(entry.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load plugin %s: %w", entry.Name, err)
+		}
+		if len(source.Metadata().PluginMetadata.LegacyFilenames) > 0 {
+			if err = m.registerLegacyFilenames(entry.Name); err != nil {
+				return nil, err
+			}
+		}
+		result.Imported = append(result.Imported, entry.Name)
+	}
+
+	for _, entry := range manifest.Runtimes {
+		label := entry.Name + "@" + entry.Version
+		source, err := m.LookupSdk(entry.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s not supported, error: %w", entry.Name, err)
+		}
+		imported, err := source.Import(sdk.Version(entry.Version), entry.Dir)
+		if err != nil {
+			return nil, err
+		}
+		if !imported {
+			logger.Debugf("Runtime %s already installed, skipping\n", label)
+			result.Skipped = append(result.Skipped, label)
+			continue
+		}
+		result.Imported = append(result.Imported, label)
+	}
+
+	m.RefreshShims()
+	return result, nil
+}
+
+// importBundlePlugin moves the plugin of entry into the shared root under the plugin
+// lock once its permissions are approved, reporting false when the plugin already exists.
+func (m *Manager) importBundlePlugin(entry *bundle.Entry, autoConfirm bool) (bool, error) {
+	lock, err := m.lockPlugin(entry.Name)
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+
+	target := filepath.Join(m.RuntimeEnvContext.PathMeta.Shared.Plugins, entry.Name)
+	if util.FileExists(target) {
+		return false, nil
+	}
+	source, err := plugin.CreatePlugin(entry.Dir, m.RuntimeEnvContext)
+	if err != nil {
+		return false, fmt.Errorf("invalid plugin %s in bundle: %w", entry.Name, err)
+	}
+	source.Close()
+	if !autoConfirm && util.IsNonInteractiveTerminal() {
+		return false, fmt.Errorf("%s plugin %s requests permissions, use the -y flag to approve them in non-interactive environments", entry.Name, source.Version)
+	}
+	title := fmt.Sprintf("%s plugin %s requests the following permissions:", entry.Name, source.Version)
+	if !confirmPermissions(title, permission.Describe(source.Permissions), autoConfirm) {
+		return false, fmt.Errorf("import of %s plugin cancelled, permissions not approved", entry.Name)
+	}
+	return true, moveBundleEntry(entry.Dir, target)
+}
+
+func moveBundleEntry(src, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), pathmeta.ReadWriteAuth); err != nil {
+		return err
+	}
+	logger.Debugf("Moving %s to %s\n", src, target)
+	if err := util.MovePath(src, target); err != nil {
+		return fmt.Errorf("failed to move %s: %w", target, err)
+	}
+	return nil
+}
+
+// registerLegacyFilenames records that the plugin name can parse legacy version files.
+func (m *Manager) registerLegacyFilenames(name string) error {
+	lfr, err := m.loadLegacyFileRecord()
+	if err != nil {
+		return err
+	}
+	lfr.Record[name] = "true"
+	if err = lfr.Save(); err != nil {
+		return fmt.Errorf("add legacy filenames failed: %w", err)
+	}
+	return nil
+}
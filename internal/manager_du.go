@@ -0,0 +1,258 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/version-fox/vfox/internal/sdk"
+	"github.com/version-fox/vfox/internal/shared/util"
+	"golang.org/x/sync/errgroup"
+)
+
+// DiskUsageKind is the kind of directory or file reported by DiskUsage.
+type DiskUsageKind string
+
+const (
+	DiskUsageSdk      DiskUsageKind = "sdk"
+	DiskUsageVersion  DiskUsageKind = "version"
+	DiskUsageAddition DiskUsageKind = "addition"
+	DiskUsagePlugin   DiskUsageKind = "plugin"
+	DiskUsageCache    DiskUsageKind = "cache"
+	DiskUsageTemp     DiskUsageKind = "temp"
+)
+
+// DiskUsageEntry is the size of a directory or file managed by vfox. The size of an
+// sdk includes its versions, and the size of a version includes its additions.
+type DiskUsageEntry struct {
+	Kind DiskUsageKind `json:"kind" yaml:"kind"`
+	// Name is the SDK or plugin name, "<sdk>@<version>" for versions and
+	// "<sdk>@<version>/<addition>" for additions.
+	Name string `json:"name" yaml:"name"`
+	Path string `json:"path" yaml:"path"`
+	Size int64  `json:"size" yaml:"size"`
+}
+
+// DiskUsage is the disk usage of the shared root and the user temp directory.
+type DiskUsage struct {
+	// Total counts every byte once, nested entries are not added twice.
+	Total   int64             `json:"total" yaml:"total"`
+	Entries []*DiskUsageEntry `json:"entries" yaml:"entries"`
+}
+
+// diskUsageScan collects entries and computes the sizes of the leaves concurrently.
+type diskUsageScan struct {
+	g       *errgroup.Group
+	mu      sync.Mutex
+	entries []*DiskUsageEntry
+}
+
+// add registers entry. Its size is computed in the background, unless it is known already.
+func (s *diskUsageScan) add(entry *DiskUsageEntry, compute bool) *DiskUsageEntry {
+	s.mu.Lock()
+	s.entries = append(s.entries, entry)
+	s.mu.Unlock()
+	if compute {
+		s.g.Go(func() error {
+			size, err := util.DirSize(entry.Path)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			entry.Size = size
+			return nil
+		})
+	}
+	return entry
+}
+
+// DiskUsage computes the size of every SDK, version, addition, plugin, available
+// cache, HTTP cache and temp directory, sorted by size in descending order.
+func (m *Manager) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	g, _ := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.NumCPU())
+	scan := &diskUsageScan{g: g}
+
+	var sdks []*sdkDiskUsage
+	installs := m.RuntimeEnvContext.PathMeta.Shared.Installs
+	for _, root := range []string{installs, filepath.Join(installs, "cache")} {
+		dirs, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, dir := range dirs {
+			if !dir.IsDir() || (root == installs && dir.Name() == "cache") {
+				continue
+			}
+			sdks = append(sdks, scanSdkUsage(scan, dir.Name(), filepath.Join(root, dir.Name())))
+		}
+	}
+	m.scanPluginUsage(scan)
+	if dirs, err := os.ReadDir(m.RuntimeEnvContext.PathMeta.User.Temp); err == nil {
+		for _, dir := range dirs {
+			if dir.IsDir() {
+				scan.add(&DiskUsageEntry{
+					Kind: DiskUsageTemp,
+					Name: dir.Name(),
+					Path: filepath.Join(m.RuntimeEnvContext.PathMeta.User.Temp, dir.Name()),
+				}, true)
+			}
+		}
+	}
+	if httpCache := m.RuntimeEnvContext.PathMeta.User.HttpCache; httpCache != "" && util.FileExists(httpCache) {
+		scan.add(&DiskUsageEntry{Kind: DiskUsageCache, Name: "http", Path: httpCache}, true)
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Sizes of sdks and versions are the sums of their children
+	for _, s := range sdks {
+		s.entry.Size = s.files
+		for _, v := range s.versions {
+			v.entry.Size = v.files
+			for _, child := range v.children {
+				v.entry.Size += child.Size
+			}
+			s.entry.Size += v.entry.Size
+		}
+	}
+
+	usage := &DiskUsage{Entries: scan.entries}
+	for _, entry := range usage.Entries {
+		switch entry.Kind {
+		case DiskUsageSdk, DiskUsagePlugin, DiskUsageCache, DiskUsageTemp:
+			usage.Total += entry.Size
+		}
+	}
+	sort.SliceStable(usage.Entries, func(i, j int) bool {
+		if usage.Entries[i].Size != usage.Entries[j].Size {
+			return usage.Entries[i].Size > usage.Entries[j].Size
+		}
+		return usage.Entries[i].Name < usage.Entries[j].Name
+	})
+	return usage, nil
+}
+
+type sdkDiskUsage struct {
+	entry    *DiskUsageEntry
+	files    int64
+	versions []*versionDiskUsage
+}
+
+type versionDiskUsage struct {
+	entry *DiskUsageEntry
+	files int64
+	// children are the runtime directories of the version, including additions.
+	children []*DiskUsageEntry
+}
+
+func scanSdkUsage(scan *diskUsageScan, name, path string) *sdkDiskUsage {
+	s := &sdkDiskUsage{
+		entry: scan.add(&DiskUsageEntry{Kind: DiskUsageSdk, Name: name, Path: path}, false),
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return s
+	}
+	for _, entry := range entries {
+		childPath := filepath.Join(path, entry.Name())
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "v-") {
+			// Lock files, staging directories and other leftovers
+			scan.g.Go(func() error {
+				size, _ := util.DirSize(childPath)
+				scan.mu.Lock()
+				s.files += size
+				scan.mu.Unlock()
+				return nil
+			})
+			continue
+		}
+		version := strings.TrimPrefix(entry.Name(), "v-")
+		label := name + "@" + version
+		v := &versionDiskUsage{
+			entry: scan.add(&DiskUsageEntry{Kind: DiskUsageVersion, Name: label, Path: childPath}, false),
+		}
+		s.versions = append(s.versions, v)
+		children, err := os.ReadDir(childPath)
+		if err != nil {
+			continue
+		}
+		for _, child := range children {
+			runtimePath := filepath.Join(childPath, child.Name())
+			if !child.IsDir() {
+				if info, err := child.Info(); err == nil {
+					v.files += info.Size()
+				}
+				continue
+			}
+			if strings.HasPrefix(child.Name(), "add-") {
+				v.children = append(v.children, scan.add(&DiskUsageEntry{
+					Kind: DiskUsageAddition,
+					Name: label + "/" + strings.TrimPrefix(child.Name(), "add-"),
+					Path: runtimePath,
+				}, true))
+				continue
+			}
+			// The main runtime is only reported as part of its version
+			main := &DiskUsageEntry{Path: runtimePath}
+			v.children = append(v.children, main)
+			scan.g.Go(func() error {
+				main.Size, _ = util.DirSize(runtimePath)
+				return nil
+			})
+		}
+	}
+	return s
+}
+
+// scanPluginUsage adds the plugin directories, reporting their available caches separately.
+func (m *Manager) scanPluginUsage(scan *diskUsageScan) {
+	pluginsDir := m.RuntimeEnvContext.PathMeta.Shared.Plugins
+	dirs, err := os.ReadDir(pluginsDir)
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		path := filepath.Join(pluginsDir, dir.Name())
+		plugin := scan.add(&DiskUsageEntry{Kind: DiskUsagePlugin, Name: dir.Name(), Path: path}, false)
+		cachePath := filepath.Join(path, sdk.AvailableCacheFilename)
+		var cache *DiskUsageEntry
+		if info, err := os.Stat(cachePath); err == nil {
+			cache = scan.add(&DiskUsageEntry{Kind: DiskUsageCache, Name: dir.Name(), Path: cachePath, Size: info.Size()}, false)
+		}
+		scan.g.Go(func() error {
+			size, err := util.DirSize(path)
+			if err != nil {
+				return err
+			}
+			if cache != nil {
+				size -= cache.Size
+			}
+			plugin.Size = size
+			return nil
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/pathmeta"
+	"github.com/version-fox/vfox/internal/sdk"
+)
+
+func writeSizedFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	root := t.TempDir()
+	meta := &pathmeta.PathMeta{
+		User: pathmeta.UserPaths{Temp: filepath.Join(root, "tmp")},
+		Shared: pathmeta.SharedPaths{
+			Installs: filepath.Join(root, "cache"),
+			Plugins:  filepath.Join(root, "plugin"),
+		},
+	}
+	version := filepath.Join(meta.Shared.Installs, "java", "v-21")
+	writeSizedFile(t, filepath.Join(version, "java-21", "bin", "java"), 1000)
+	writeSizedFile(t, filepath.Join(version, "add-maven-3", "bin", "mvn"), 200)
+	writeSizedFile(t, filepath.Join(version, sdk.ReceiptFilename), 10)
+	writeSizedFile(t, filepath.Join(meta.Shared.Installs, "java", ".v-21.lock"), 0)
+	writeSizedFile(t, filepath.Join(meta.Shared.Plugins, "java", "metadata.lua"), 50)
+	writeSizedFile(t, filepath.Join(meta.Shared.Plugins, "java", sdk.AvailableCacheFilename), 30)
+	writeSizedFile(t, filepath.Join(meta.User.Temp, "20260101-1", "link"), 5)
+
+	manager := &Manager{
+		RuntimeEnvContext: &env.RuntimeEnvContext{PathMeta: meta},
+		openSdks:          make(map[string]sdk.Sdk),
+	}
+	usage, err := manager.DiskUsage(context.Background())
+	if err != nil {
+		t.Fatalf("DiskUsage() failed: %v", err)
+	}
+
+	want := map[DiskUsageKind]map[string]int64{
+		DiskUsageSdk:      {"java": 1210},
+		DiskUsageVersion:  {"java@21": 1210},
+		DiskUsageAddition: {"java@21/maven-3": 200},
+		DiskUsagePlugin:   {"java": 50},
+		DiskUsageCache:    {"java": 30},
+		DiskUsageTemp:     {"20260101-1": 5},
+	}
+	if len(usage.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(usage.Entries))
+	}
+	for _, entry := range usage.Entries {
+		if size, ok := want[entry.Kind][entry.Name]; !ok || size != entry.Size {
+			t.Errorf("unexpected entry %s %s with size %d", entry.Kind, entry.Name, entry.Size)
+		}
+	}
+	if usage.Total != 1210+50+30+5 {
+		t.Errorf("unexpected total %d", usage.Total)
+	}
+	if usage.Entries[0].Size < usage.Entries[len(usage.Entries)-1].Size {
+		t.Error("expected entries sorted by size")
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/version-fox/vfox/internal/pathmeta"
+	"github.com/version-fox/vfox/internal/shared/logger"
+	"github.com/version-fox/vfox/internal/shared/shim"
+	"github.com/version-fox/vfox/internal/shared/util"
+)
+
+const (
+	// ShimExecCommand is the hidden command invoked by every generated shim.
+	ShimExecCommand = "shim-exec"
+
+	shimRecordFilename = ".shims"
+)
+
+// Reshim regenerates the shims directory, creating one shim for every executable
+// provided by any installed version of any SDK. It returns the number of shims generated.
+func (m *Manager) Reshim() (int, error) {
+	shimsDir := m.RuntimeEnvContext.PathMeta.User.Shims
+	logger.Debugf("Regenerating shims in: %s\n", shimsDir)
+
+	providers, err := m.collectExecutables()
+	if err != nil {
+		return 0, err
+	}
+
+	if err = os.RemoveAll(shimsDir); err != nil {
+		return 0, fmt.Errorf("failed to clear shims directory: %w", err)
+	}
+	if err = os.MkdirAll(shimsDir, pathmeta.ReadWriteAuth); err != nil {
+		return 0, fmt.Errorf("failed to create shims directory: %w", err)
+	}
+
+	record, err := pathmeta.NewFileRecord(filepath.Join(shimsDir, shimRecordFilename))
+	if err != nil {
+		return 0, err
+	}
+	vfoxPath := m.RuntimeEnvContext.PathMeta.Executable
+	for name, sdkNames := range providers {
+		s := shim.NewCommandShim(name, vfoxPath, shimsDir, ShimExecCommand, name)
+		if err = s.Generate(); err != nil {
+			return 0, fmt.Errorf("failed to generate shim for %s: %w", name, err)
+		}
+		record.Record[name] = strings.Join(sdkNames, ",")
+	}
+	if err = record.Save(); err != nil {
+		return 0, fmt.Errorf("failed to save shim record: %w", err)
+	}
+	logger.Debugf("Generated %d shims\n", len(providers))
+	return len(providers), nil
+}
+
+// ShimProviders returns the names of the SDKs that provide the given shim,
+// as recorded by the last Reshim.
+func (m *Manager) ShimProviders(name string) []string {
+	record, err := pathmeta.NewFileRecord(filepath.Join(m.RuntimeEnvContext.PathMeta.User.Shims, shimRecordFilename))
+	if err != nil {
+		return nil
+	}
+	value, ok := record.Record[name]
+	if !ok || value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// collectExecutables walks the PATH entries of every installed runtime and
+// returns a map of executable name to the sorted names of the SDKs providing it.
+func (m *Manager) collectExecutables() (map[string][]string, error) {
+	allSdk, err := m.LoadAllSdk()
+	if err != nil {
+		return nil, err
+	}
+	providers := make(map[string]util.Set[string])
+	for _, s := range allSdk {
+		sdkName := s.Metadata().Name
+		for _, version := range s.InstalledList() {
+			runtimePackage, err := s.GetRuntimePackage(version)
+			if err != nil {
+				logger.Debugf("Skip %s@%s: %v\n", sdkName, version, err)
+				continue
+			}
+			envKeys, err := s.EnvKeys(runtimePackage)
+			if err != nil {
+				logger.Debugf("Skip %s@%s: %v\n", sdkName, version, err)
+				continue
+			}
+			for _, dir := range envKeys.Paths.Slice() {
+				for _, name := range listExecutables(dir) {
+					if _, ok := providers[name]; !ok {
+						providers[name] = util.NewSet[string]()
+					}
+					providers[name].Add(sdkName)
+				}
+			}
+		}
+	}
+	result := make(map[string][]string, len(providers))
+	for name, sdkNames := range providers {
+		names := sdkNames.Slice()
+		sort.Strings(names)
+		result[name] = names
+	}
+	return result, nil
+}
+
+// listExecutables returns the names of the executable files directly under dir.
+func listExecutables(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		if util.IsExecutable(path) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+// RefreshShims regenerates the shims if the user has opted in to them by running
+// `vfox reshim` at least once. Errors are only logged, since shims are best effort.
+func (m *Manager) RefreshShims() {
+	if !util.FileExists(m.RuntimeEnvContext.PathMeta.User.Shims) {
+		return
+	}
+	if _, err := m.Reshim(); err != nil {
+		logger.Debugf("Failed to refresh shims: %v\n", err)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package internal
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/version-fox/vfox/internal/pathmeta"
+	"github.com/version-fox/vfox/internal/sdk"
+	"github.com/version-fox/vfox/internal/shared/flock"
+	"github.com/version-fox/vfox/internal/shared/logger"
+)
+
+const (
+	lastUsedFilename = ".last_used"
+	// lastUsedPrecision is how stale a recorded timestamp may get before it is rewritten,
+	// so that the prompt hook does not write the file on every prompt.
+	lastUsedPrecision = time.Hour
+	// lastUsedLockTimeout bounds how long a command waits for another process writing
+	// the record, as a missed timestamp is harmless.
+	lastUsedLockTimeout = 5 * time.Second
+)
+
+// usageTracker batches the last-used timestamps recorded while the manager is open.
+type usageTracker struct {
+	pending map[string]time.Time
+	record  *pathmeta.FileRecord
+}
+
+func usageKey(name string, version sdk.Version) string {
+	return strings.ToLower(name) + "@" + string(version)
+}
+
+// MarkUsed records that version of the SDK name was resolved for use. The timestamp
+// is only kept in memory and written to disk by Close.
+func (m *Manager) MarkUsed(name string, version sdk.Version) {
+	if name == "" || version == "" {
+		return
+	}
+	m.usageMu.Lock()
+	defer m.usageMu.Unlock()
+	if m.usage.pending == nil {
+		m.usage.pending = make(map[string]time.Time)
+	}
+	m.usage.pending[usageKey(name, version)] = time.Now()
+}
+
+// LastUsed returns when version of the SDK name was last used, and false if it never was.
+func (m *Manager) LastUsed(name string, version sdk.Version) (time.Time, bool) {
+	m.usageMu.Lock()
+	defer m.usageMu.Unlock()
+	key := usageKey(name, version)
+	if t, ok := m.usage.pending[key]; ok {
+		return t, true
+	}
+	record, err := m.usageRecord()
+	if err != nil {
+		return time.Time{}, false
+	}
+	value, ok := record.Record[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+func (m *Manager) usageRecordPath() string {
+	return filepath.Join(m.RuntimeEnvContext.PathMeta.User.Home, lastUsedFilename)
+}
+
+// usageRecord loads the last-used record once. The caller must hold usageMu.
+func (m *Manager) usageRecord() (*pathmeta.FileRecord, error) {
+	if m.usage.record != nil {
+		return m.usage.record, nil
+	}
+	record, err := pathmeta.NewFileRecord(m.usageRecordPath())
+	if err != nil {
+		return nil, err
+	}
+	m.usage.record = record
+	return record, nil
+}
+
+// flushUsage writes the pending timestamps, skipping those recorded less than
+// lastUsedPrecision ago, and does not touch the file if nothing changed. Shells
+// flush concurrently, so the record is reloaded and replaced under a file lock.
+func (m *Manager) flushUsage() {
+	m.usageMu.Lock()
+	defer m.usageMu.Unlock()
+	if len(m.usage.pending) == 0 {
+		return
+	}
+	path := m.usageRecordPath()
+	lock, err := flock.Acquire(path+".lock", lastUsedLockTimeout, nil)
+	if err != nil {
+		logger.Debugf("Failed to lock last-used record: %v\n", err)
+		return
+	}
+	defer lock.Unlock()
+	record, err := pathmeta.NewFileRecord(path)
+	if err != nil {
+		logger.Debugf("Failed to load last-used record: %v\n", err)
+		return
+	}
+	m.usage.record = record
+	changed := false
+	for key, t := range m.usage.pending {
+		if value, ok := record.Record[key]; ok {
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && t.Sub(time.Unix(seconds, 0)) < lastUsedPrecision {
+				continue
+			}
+		}
+		record.Record[key] = strconv.FormatInt(t.Unix(), 10)
+		changed = true
+	}
+	m.usage.pending = nil
+	if !changed {
+		return
+	}
+	logger.Debugf("Saving last-used record: %s\n", record.Path)
+	if err = record.SaveAtomic(); err != nil {
+		logger.Debugf("Failed to save last-used record: %v\n", err)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/pathmeta"
+	"github.com/version-fox/vfox/internal/sdk"
+)
+
+func newUsageTestManager(t *testing.T, userHome string) *Manager {
+	t.Helper()
+	return &Manager{
+		RuntimeEnvContext: &env.RuntimeEnvContext{
+			PathMeta: &pathmeta.PathMeta{User: pathmeta.UserPaths{Home: userHome}},
+		},
+		openSdks: make(map[string]sdk.Sdk),
+	}
+}
+
+func TestMarkUsed(t *testing.T) {
+	userHome := t.TempDir()
+	recordPath := filepath.Join(userHome, lastUsedFilename)
+
+	manager := newUsageTestManager(t, userHome)
+	if _, ok := manager.LastUsed("nodejs", "20.1.0"); ok {
+		t.Fatal("expected no last-used time before use")
+	}
+	manager.MarkUsed("NodeJS", "20.1.0")
+	manager.Close()
+
+	reopened := newUsageTestManager(t, userHome)
+	used, ok := reopened.LastUsed("nodejs", "20.1.0")
+	if !ok || time.Since(used) > time.Minute {
+		t.Fatalf("expected recent last-used time, got %v, %v", used, ok)
+	}
+
+	// A recent timestamp is not rewritten, so the file is left untouched
+	before, err := os.Stat(recordPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chtimes(recordPath, before.ModTime().Add(-time.Hour), before.ModTime().Add(-time.Hour))
+	reopened.MarkUsed("nodejs", "20.1.0")
+	reopened.Close()
+	after, err := os.Stat(recordPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.ModTime().Before(before.ModTime()) {
+		t.Fatal("expected the last-used record not to be rewritten")
+	}
+}
+
+func TestMarkUsedRewritesStaleTimestamp(t *testing.T) {
+	userHome := t.TempDir()
+	stale := time.Now().Add(-2 * lastUsedPrecision).Unix()
+	content := "nodejs@20.1.0 " + strconv.FormatInt(stale, 10) + "\n"
+	if err := os.WriteFile(filepath.Join(userHome, lastUsedFilename), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := newUsageTestManager(t, userHome)
+	manager.MarkUsed("nodejs", "20.1.0")
+	manager.Close()
+
+	used, ok := newUsageTestManager(t, userHome).LastUsed("nodejs", "20.1.0")
+	if !ok || used.Unix() == stale {
+		t.Fatalf("expected stale timestamp to be updated, got %v, %v", used, ok)
+	}
+}
+
+func TestMarkUsedConcurrently(t *testing.T) {
+	userHome := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			manager := newUsageTestManager(t, userHome)
+			manager.MarkUsed("nodejs", sdk.Version(strconv.Itoa(i)))
+			manager.Close()
+		}(i)
+	}
+	wg.Wait()
+
+	reopened := newUsageTestManager(t, userHome)
+	for i := 0; i < 8; i++ {
+		if _, ok := reopened.LastUsed("nodejs", sdk.Version(strconv.Itoa(i))); !ok {
+			t.Errorf("expected the timestamp of nodejs@%d to be kept", i)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/version-fox/vfox/internal/shared/util"
@@ -44,6 +45,31 @@ func (m *FileRecord) Save() error {
 	}
 	defer file.Close()
 
+	return m.write(file)
+}
+
+// SaveAtomic is like Save, but writes a temporary file and renames it over the record,
+// so that readers never see a truncated record.
+func (m *FileRecord) SaveAtomic() error {
+	if m.isInitEmpty && len(m.Record) == 0 {
+		return nil
+	}
+	file, err := os.CreateTemp(filepath.Dir(m.Path), filepath.Base(m.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file record %s: %w", m.Path, err)
+	}
+	defer os.Remove(file.Name())
+	if err = m.write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), m.Path)
+}
+
+func (m *FileRecord) write(file *os.File) error {
 	for k, v := range m.Record {
 		_, err := fmt.Fprintf(file, "%s %s\n", k, v)
 		if err != nil {
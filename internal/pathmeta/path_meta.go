@@ -30,9 +30,11 @@ import (
 )
 
 type UserPaths struct {
-	Home   string // ~/.vfox
-	Temp   string // ~/.vfox/tmp (session temporary)
-	Config string // ~/.vfox/config.yaml (user override config)
+	Home      string // ~/.vfox
+	Temp      string // ~/.vfox/tmp (session temporary)
+	Config    string // ~/.vfox/config.yaml (user override config)
+	Shims     string // ~/.vfox/shims (dynamic shims for non-hook environments)
+	HttpCache string // ~/.vfox/httpcache (cached HTTP responses to plugins)
 }
 
 type SharedPaths struct {
@@ -66,6 +68,8 @@ const (
 	installedDirPrefix  = "cache"  // Shared installed directory
 	configFilePrefix    = "config.yaml"
 	symlinkSdkDirPrefix = "sdks"
+	shimsDirPrefix      = "shims"
+	httpCacheDirPrefix  = "httpcache"
 
 	ReadWriteAuth = 0755 // can write and read
 )
@@ -120,9 +124,11 @@ func NewPathMeta(userHome, sharedRoot, currentDir string, pid int) (*PathMeta, e
 	}
 	meta := &PathMeta{
 		User: UserPaths{
-			Home:   vfoxUserHome,
-			Temp:   filepath.Join(vfoxUserHome, tmpDirPrefix),
-			Config: filepath.Join(vfoxUserHome, configFilePrefix),
+			Home:      vfoxUserHome,
+			Temp:      filepath.Join(vfoxUserHome, tmpDirPrefix),
+			Config:    filepath.Join(vfoxUserHome, configFilePrefix),
+			Shims:     filepath.Join(vfoxUserHome, shimsDirPrefix),
+			HttpCache: filepath.Join(vfoxUserHome, httpCacheDirPrefix),
 		},
 		Shared: SharedPaths{
 			Root:     sharedRoot,
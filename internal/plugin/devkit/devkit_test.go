@@ -0,0 +1,146 @@
+/*
+ *
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ *
+ */
+
+package devkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/version-fox/vfox/internal/config"
+	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/plugin"
+)
+
+func newTestEnvContext() *env.RuntimeEnvContext {
+	return &env.RuntimeEnvContext{
+		UserConfig:     config.DefaultConfig,
+		RuntimeVersion: "1.0.0",
+	}
+}
+
+func scaffold(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "demo")
+	files, err := Scaffold(dir, "demo", "1.0.0")
+	if err != nil {
+		t.Fatalf("Scaffold failed: %v", err)
+	}
+	if len(files) != len(plugin.HookFuncMap)+1 {
+		t.Errorf("Expected metadata and %d hooks, got %v", len(plugin.HookFuncMap), files)
+	}
+	return dir
+}
+
+func TestScaffold(t *testing.T) {
+	dir := scaffold(t)
+	for _, hf := range plugin.HookFuncMap {
+		content, err := os.ReadFile(filepath.Join(dir, "hooks", hf.Filename+".lua"))
+		if err != nil {
+			t.Fatalf("Missing hook %s: %v", hf.Name, err)
+		}
+		if !strings.Contains(string(content), "function PLUGIN:"+hookFunctionName(hf)+"(ctx)") {
+			t.Errorf("Hook file of %s does not define it:\n%s", hf.Name, content)
+		}
+	}
+	if _, err := Scaffold(dir, "demo", "1.0.0"); err == nil {
+		t.Errorf("Expected scaffolding into a non-empty directory to fail")
+	}
+	if _, err := Scaffold(t.TempDir(), "1demo", "1.0.0"); err == nil {
+		t.Errorf("Expected an invalid name to fail")
+	}
+}
+
+func TestRunReplay(t *testing.T) {
+	dir := scaffold(t)
+
+	report, err := Run(dir, newTestEnvContext(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Failed() || len(report.Misses) != 1 {
+		t.Fatalf("Expected Available to fail without fixtures, got %+v", report)
+	}
+
+	fixtures := []*Fixture{{
+		Method: http.MethodGet,
+		URL:    "https://example.com/demo/versions.json",
+		Status: http.StatusOK,
+		Body:   `["1.2.0", "1.1.0"]`,
+	}}
+	if err = SaveFixtures(filepath.Join(dir, FixturesFilename), fixtures); err != nil {
+		t.Fatal(err)
+	}
+	report, err = Run(dir, newTestEnvContext(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range report.Hooks {
+		if h.Err != nil {
+			t.Errorf("Hook %s failed: %v", h.Hook, h.Err)
+		}
+	}
+	preInstall := report.Hooks[1].Output.(*plugin.PreInstallHookResult)
+	if preInstall.Version != "1.2.0" {
+		t.Errorf("Expected the first available version to be installed, got %s", preInstall.Version)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte{0xff, 0x00, 0x01})
+	}))
+	defer upstream.Close()
+
+	recorder := NewRecorder(nil)
+	client := &http.Client{Transport: recorder}
+	resp, err := client.Get(upstream.URL + "/archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	fixtures := recorder.Fixtures()
+	if len(fixtures) != 1 || fixtures[0].BodyBase64 == "" {
+		t.Fatalf("Expected a binary fixture, got %+v", fixtures)
+	}
+	upstream.Close()
+
+	standIn, err := NewStandIn(fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer standIn.Close()
+	client = &http.Client{Transport: standIn.Transport()}
+	resp, err = client.Get(upstream.URL + "/archive")
+	if err != nil {
+		t.Fatalf("Expected the fixture to be replayed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "application/octet-stream" {
+		t.Errorf("Expected recorded headers, got %v", resp.Header)
+	}
+	if _, err = client.Get(upstream.URL + "/other"); err == nil {
+		t.Errorf("Expected a request without fixture to fail")
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ *
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ *
+ */
+
+package devkit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// FixturesFilename is where the HTTP fixtures of a plugin are recorded, relative to the plugin directory.
+var FixturesFilename = filepath.Join("testdata", "http_fixtures.json")
+
+const (
+	fixtureURLHeader     = "X-Vfox-Fixture-Url"
+	fixtureMissingHeader = "X-Vfox-Fixture-Missing"
+)
+
+// Fixture is a recorded HTTP response.
+type Fixture struct {
+	Method string            `json:"method"`
+	URL    string            `json:"url"`
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"`
+	// BodyBase64 holds bodies which are not valid UTF-8, such as archives.
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+}
+
+func (f *Fixture) key() string {
+	return f.Method + " " + f.URL
+}
+
+func (f *Fixture) body() ([]byte, error) {
+	if f.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(f.BodyBase64)
+	}
+	return []byte(f.Body), nil
+}
+
+// LoadFixtures reads the fixtures recorded at path. A missing file has no fixtures.
+func LoadFixtures(path string) ([]*Fixture, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []*Fixture
+	if err = json.Unmarshal(content, &fixtures); err != nil {
+		return nil, fmt.Errorf("parse fixtures %s: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// SaveFixtures writes fixtures to path, sorted so that recordings diff well.
+func SaveFixtures(path string, fixtures []*Fixture) error {
+	sort.SliceStable(fixtures, func(i, j int) bool {
+		return fixtures[i].key() < fixtures[j].key()
+	})
+	content, err := json.MarshalIndent(fixtures, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// StandIn is a local HTTP server replaying fixtures in place of the real servers.
+type StandIn struct {
+	fixtures map[string]*Fixture
+	listener net.Listener
+	server   *http.Server
+	// transport talks to the stand-in server directly, whatever the proxy settings.
+	transport *http.Transport
+
+	mu     sync.Mutex
+	misses []string
+}
+
+// NewStandIn starts a stand-in server for fixtures on a random local port.
+func NewStandIn(fixtures []*Fixture) (*StandIn, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &StandIn{
+		fixtures:  make(map[string]*Fixture, len(fixtures)),
+		listener:  listener,
+		transport: &http.Transport{},
+	}
+	for _, f := range fixtures {
+		s.fixtures[f.key()] = f
+	}
+	s.server = &http.Server{Handler: http.HandlerFunc(s.serve)}
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+	return s, nil
+}
+
+func (s *StandIn) serve(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.Header.Get(fixtureURLHeader)
+	f, ok := s.fixtures[key]
+	if !ok {
+		s.mu.Lock()
+		s.misses = append(s.misses, key)
+		s.mu.Unlock()
+		w.Header().Set(fixtureMissingHeader, "1")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	body, err := f.body()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for k, v := range f.Header {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(f.Status)
+	_, _ = w.Write(body)
+}
+
+// Transport sends every request to the stand-in server, which answers with the fixture of
+// the original URL. Requests without a fixture fail.
+func (s *StandIn) Transport() http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		original := req.URL.String()
+		r := req.Clone(req.Context())
+		r.URL.Scheme = "http"
+		r.URL.Host = s.listener.Addr().String()
+		r.Host = r.URL.Host
+		r.Header.Set(fixtureURLHeader, original)
+		resp, err := s.transport.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Header.Get(fixtureMissingHeader) != "" {
+			resp.Body.Close()
+			return nil, fmt.Errorf("no recorded fixture for %s %s, record it with `vfox plugin test --record`", req.Method, original)
+		}
+		resp.Request = req
+		return resp, nil
+	})
+}
+
+// Misses returns the requests which had no fixture.
+func (s *StandIn) Misses() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.misses...)
+}
+
+func (s *StandIn) Close() error {
+	s.transport.CloseIdleConnections()
+	return s.server.Close()
+}
+
+// Recorder is a transport recording the responses of the real servers as fixtures.
+type Recorder struct {
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	fixtures map[string]*Fixture
+}
+
+// NewRecorder records the requests sent through base, or http.DefaultTransport if nil.
+func NewRecorder(base http.RoundTripper) *Recorder {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Recorder{base: base, fixtures: make(map[string]*Fixture)}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	f := &Fixture{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: make(map[string]string),
+	}
+	for _, k := range []string{"Content-Type", "Content-Disposition", "Location", "Etag", "Last-Modified"} {
+		if v := resp.Header.Get(k); v != "" {
+			f.Header[k] = v
+		}
+	}
+	if utf8.Valid(body) {
+		f.Body = string(body)
+	} else {
+		f.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+	r.mu.Lock()
+	r.fixtures[f.key()] = f
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Fixtures returns the recorded fixtures.
+func (r *Recorder) Fixtures() []*Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fixtures := make([]*Fixture, 0, len(r.fixtures))
+	for _, f := range r.fixtures {
+		fixtures = append(fixtures, f)
+	}
+	return fixtures
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,231 @@
+/*
+ *
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ *
+ */
+
+package devkit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/plugin"
+	"github.com/version-fox/vfox/internal/shared/util"
+)
+
+// Options configures Run.
+type Options struct {
+	// Version is passed to the hooks taking a version, the first available version if empty.
+	Version string
+	// Record sends HTTP requests to the real servers and saves the responses as fixtures,
+	// instead of replaying the fixtures.
+	Record bool
+}
+
+// HookResult is the outcome of a hook run with a sample context.
+type HookResult struct {
+	Hook     string
+	Skipped  bool // the hook is optional and not implemented, or has no sample context
+	Err      error
+	Duration time.Duration
+	Output   any
+}
+
+// Report is the outcome of Run.
+type Report struct {
+	Plugin *plugin.Metadata
+	Hooks  []*HookResult
+	// Misses are the requests which had no fixture.
+	Misses []string
+	// Recorded is the number of fixtures saved with Options.Record.
+	Recorded int
+}
+
+// Failed reports whether a hook failed.
+func (r *Report) Failed() bool {
+	for _, h := range r.Hooks {
+		if h.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Run loads the plugin in dir and runs each of its hooks with sample contexts. Files are only
+// written to a temporary directory, and HTTP is served from the fixtures of the plugin.
+// The error is only set if the plugin cannot be loaded, failing hooks are in the report.
+func Run(dir string, envCtx *env.RuntimeEnvContext, opts Options) (*Report, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	fixturesPath := filepath.Join(dir, FixturesFilename)
+
+	ctx := *envCtx
+	var recorder *Recorder
+	var standIn *StandIn
+	if opts.Record {
+		recorder = NewRecorder(envCtx.HttpClient().Transport)
+		ctx.HttpTransport = recorder
+	} else {
+		fixtures, err := LoadFixtures(fixturesPath)
+		if err != nil {
+			return nil, err
+		}
+		if standIn, err = NewStandIn(fixtures); err != nil {
+			return nil, fmt.Errorf("start stand-in server: %w", err)
+		}
+		defer standIn.Close()
+		ctx.HttpTransport = standIn.Transport()
+	}
+
+	wrapper, err := plugin.CreatePlugin(dir, &ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load plugin: %w", err)
+	}
+	defer wrapper.Close()
+
+	sandbox, err := os.MkdirTemp("", "vfox-plugin-test-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(sandbox)
+
+	r := &runner{wrapper: wrapper, dir: dir, sandbox: sandbox, version: opts.Version}
+	report := &Report{Plugin: wrapper.Metadata, Hooks: r.run()}
+
+	if standIn != nil {
+		report.Misses = standIn.Misses()
+	}
+	if recorder != nil {
+		fixtures := recorder.Fixtures()
+		if err = SaveFixtures(fixturesPath, fixtures); err != nil {
+			return report, fmt.Errorf("save fixtures: %w", err)
+		}
+		report.Recorded = len(fixtures)
+	}
+	return report, nil
+}
+
+type runner struct {
+	wrapper *plugin.Wrapper
+	dir     string
+	sandbox string
+	version string
+	results []*HookResult
+}
+
+func (r *runner) run() []*HookResult {
+	r.available()
+	if r.version == "" {
+		r.version = "latest"
+	}
+	main := &plugin.InstalledPackageItem{
+		Name:    r.wrapper.Name,
+		Version: r.version,
+		Path:    filepath.Join(r.sandbox, "installs", r.wrapper.Name+"-"+r.version),
+	}
+	sdkInfo := map[string]*plugin.InstalledPackageItem{main.Name: main}
+
+	r.call("PreInstall", "PreInstall", func() (any, error) {
+		result, err := r.wrapper.PreInstall(&plugin.PreInstallHookCtx{Version: r.version})
+		if err == nil && (result == nil || result.PreInstallPackageItem == nil || result.Version == "") {
+			err = errors.New("no version returned")
+		}
+		return result, err
+	})
+	r.call("PostInstall", "PostInstall", func() (any, error) {
+		if err := os.MkdirAll(main.Path, 0755); err != nil {
+			return nil, err
+		}
+		return nil, r.wrapper.PostInstall(&plugin.PostInstallHookCtx{RootPath: main.Path, SdkInfo: sdkInfo})
+	})
+	r.call("EnvKeys", "EnvKeys", func() (any, error) {
+		return r.wrapper.EnvKeys(&plugin.EnvKeysHookCtx{Main: main, Path: main.Path, SdkInfo: sdkInfo})
+	})
+	r.call("PreUse", "PreUse", func() (any, error) {
+		return r.wrapper.PreUse(&plugin.PreUseHookCtx{
+			Cwd:           r.sandbox,
+			Scope:         "project",
+			Version:       r.version,
+			InstalledSdks: map[string]*plugin.InstalledPackageItem{r.version: main},
+		})
+	})
+	r.parseLegacyFiles()
+	r.call("PreUninstall", "PreUninstall", func() (any, error) {
+		return nil, r.wrapper.PreUninstall(&plugin.PreUninstallHookCtx{Main: main, SdkInfo: sdkInfo})
+	})
+	return r.results
+}
+
+func (r *runner) available() {
+	r.call("Available", "Available", func() (any, error) {
+		result, err := r.wrapper.Available(&plugin.AvailableHookCtx{})
+		if err == nil && len(result) == 0 {
+			err = errors.New("no available version returned")
+		}
+		if err == nil && r.version == "" {
+			r.version = result[0].Version
+		}
+		return result, err
+	})
+}
+
+// parseLegacyFiles parses testdata/<filename> for every legacy filename of the plugin,
+// or a file containing the sample version if there is none. The hook is skipped if the
+// plugin has no legacy filenames.
+func (r *runner) parseLegacyFiles() {
+	if !r.wrapper.HasFunction("ParseLegacyFile") || len(r.wrapper.LegacyFilenames) == 0 {
+		r.results = append(r.results, &HookResult{Hook: "ParseLegacyFile", Skipped: true})
+		return
+	}
+	for _, filename := range r.wrapper.LegacyFilenames {
+		r.call("ParseLegacyFile "+filename, "ParseLegacyFile", func() (any, error) {
+			path := filepath.Join(r.dir, "testdata", filename)
+			if !util.FileExists(path) {
+				path = filepath.Join(r.sandbox, filename)
+				if err := os.WriteFile(path, []byte(r.version+"\n"), 0644); err != nil {
+					return nil, err
+				}
+			}
+			return r.wrapper.ParseLegacyFile(&plugin.ParseLegacyFileHookCtx{
+				Filepath: path,
+				Filename: filename,
+				Strategy: "specified",
+				GetInstalledVersions: func() []string {
+					return []string{r.version}
+				},
+			})
+		})
+	}
+}
+
+// call runs fn as hook, unless the plugin does not implement function.
+func (r *runner) call(hook, function string, fn func() (any, error)) {
+	result := &HookResult{Hook: hook}
+	r.results = append(r.results, result)
+	if !r.wrapper.HasFunction(function) {
+		result.Skipped = true
+		return
+	}
+	start := time.Now()
+	result.Output, result.Err = fn()
+	result.Duration = time.Since(start)
+}
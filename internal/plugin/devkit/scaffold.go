@@ -0,0 +1,110 @@
+/*
+ *
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ *
+ */
+
+// Package devkit helps writing plugins: it scaffolds new plugins and runs the hooks of a
+// plugin with sample contexts, serving HTTP from recorded fixtures.
+package devkit
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/version-fox/vfox/internal/plugin"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var validName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_\-]*$`)
+
+type scaffoldData struct {
+	Name           string
+	HookName       string
+	RuntimeVersion string
+}
+
+// genericHook is used for hooks without a template.
+const genericHook = `--- {{.HookName}} hook, see https://vfox.dev/plugins/create/howto.html
+--- @param ctx table Context information
+function PLUGIN:{{.HookName}}(ctx)
+end
+`
+
+// Scaffold creates a plugin named name in dir, with a metadata.lua and a hook file for
+// every hook in plugin.HookFuncMap. It returns the created files, and fails if dir is
+// not empty so that no work is overwritten.
+func Scaffold(dir, name, runtimeVersion string) ([]string, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid plugin name [%s]", name)
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("directory %s is not empty", dir)
+	}
+	data := scaffoldData{Name: name, RuntimeVersion: runtimeVersion}
+
+	files := map[string]string{"metadata.lua": "templates/metadata.lua"}
+	hookNames := make(map[string]string)
+	for _, hf := range plugin.HookFuncMap {
+		target := filepath.Join("hooks", hf.Filename+".lua")
+		files[target] = "templates/hooks/" + hf.Filename + ".lua"
+		hookNames[target] = hookFunctionName(hf)
+	}
+
+	created := make([]string, 0, len(files))
+	for target, source := range files {
+		content, err := templates.ReadFile(source)
+		if err != nil {
+			content = []byte(genericHook)
+		}
+		data.HookName = hookNames[target]
+		tmpl, err := template.New(target).Parse(string(content))
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, target)
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		if err = os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return nil, err
+		}
+		created = append(created, path)
+	}
+	sort.Strings(created)
+	return created, nil
+}
+
+// hookFunctionName returns the name of the Lua function implementing hf,
+// which is capitalized even for hooks registered in lower camel case.
+func hookFunctionName(hf plugin.HookFunc) string {
+	if hf.Name == "" {
+		return hf.Name
+	}
+	return strings.ToUpper(hf.Name[:1]) + hf.Name[1:]
+}
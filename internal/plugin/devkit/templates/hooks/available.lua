@@ -0,0 +1,27 @@
+local http = require("http")
+local json = require("json")
+
+--- Returns all available versions provided by this plugin.
+--- Run `vfox plugin test --record` once to record the HTTP responses used by `vfox plugin test`.
+--- @param ctx table Context information
+--- @field ctx.args table User arguments of `vfox search`
+--- @return table Descriptions of available versions
+function PLUGIN:Available(ctx)
+    local resp, err = http.get({
+        url = "https://example.com/{{.Name}}/versions.json"
+    })
+    if err ~= nil then
+        error("failed to fetch versions: " .. err)
+    end
+    if resp.status_code ~= 200 then
+        error("failed to fetch versions, status code: " .. resp.status_code)
+    end
+    local result = {}
+    for _, v in ipairs(json.decode(resp.body)) do
+        table.insert(result, {
+            version = v,
+            note = "",
+        })
+    end
+    return result
+end
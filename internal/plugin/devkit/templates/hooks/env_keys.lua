@@ -0,0 +1,15 @@
+--- Returns the environment variables of an installed version.
+--- Note: Be sure to distinguish between environment variable settings for different platforms!
+--- @param ctx table Context information
+--- @field ctx.path string SDK installation directory
+--- @field ctx.main table Main runtime information, with path, version and name
+--- @return table Environment variables
+function PLUGIN:EnvKeys(ctx)
+    local mainPath = ctx.path
+    return {
+        {
+            key = "PATH",
+            value = mainPath .. "/bin"
+        },
+    }
+end
@@ -0,0 +1,18 @@
+--- Parses a legacy version file listed in PLUGIN.legacyFilenames, such as .nvmrc.
+--- Can be removed if not needed.
+--- @param ctx table Context information
+--- @field ctx.filepath string Path of the file
+--- @field ctx.filename string Name of the file
+--- @field ctx.getInstalledVersions function Returns the installed versions
+--- @return table Version information
+function PLUGIN:ParseLegacyFile(ctx)
+    local file = io.open(ctx.filepath, "r")
+    if file == nil then
+        return {}
+    end
+    local content = file:read("*a")
+    file:close()
+    return {
+        version = content:gsub("%s+", "")
+    }
+end
@@ -0,0 +1,9 @@
+--- Called after the downloaded files are extracted, to perform additional steps such as compiling.
+--- Can be removed if not needed.
+--- @param ctx table Context information
+--- @field ctx.rootPath string SDK installation directory
+--- @field ctx.sdkInfo table Installed runtimes, keyed by name
+function PLUGIN:PostInstall(ctx)
+    local rootPath = ctx.rootPath
+    local sdkInfo = ctx.sdkInfo["{{.Name}}"]
+end
@@ -0,0 +1,14 @@
+--- Returns the information needed to install a version, such as its download URL.
+--- If a checksum is provided, vfox verifies the download for you.
+--- @param ctx table Context information
+--- @field ctx.version string User input version
+--- @return table Version information
+function PLUGIN:PreInstall(ctx)
+    local version = ctx.version
+    return {
+        version = version,
+        url = "https://example.com/{{.Name}}/" .. version .. "/{{.Name}}-" .. version .. "-" .. RUNTIME.osType .. "-" .. RUNTIME.archType .. ".tar.gz",
+        -- sha256 = "",
+        note = "",
+    }
+end
@@ -0,0 +1,7 @@
+--- Called before a version is uninstalled, to clean up files outside of its installation directory.
+--- Can be removed if not needed.
+--- @param ctx table Context information
+--- @field ctx.main table Main runtime information, with path, version and name
+--- @field ctx.sdkInfo table Installed runtimes, keyed by name
+function PLUGIN:PreUninstall(ctx)
+end
@@ -0,0 +1,14 @@
+--- Called by `vfox use` to resolve the version to use, such as a partial version.
+--- Can be removed if not needed.
+--- @param ctx table Context information
+--- @field ctx.version string User input version
+--- @field ctx.previousVersion string Version in use
+--- @field ctx.installedSdks table Installed versions, keyed by version
+--- @field ctx.cwd string Working directory
+--- @field ctx.scope string global, project or session
+--- @return table Version information
+function PLUGIN:PreUse(ctx)
+    return {
+        version = ctx.version
+    }
+end
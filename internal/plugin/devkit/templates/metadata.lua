@@ -0,0 +1,36 @@
+--- !!! DO NOT EDIT OR RENAME !!!
+PLUGIN = {}
+
+--- !!! MUST BE SET !!!
+--- Plugin name
+PLUGIN.name = "{{.Name}}"
+--- Plugin version
+PLUGIN.version = "0.0.1"
+--- Plugin homepage
+PLUGIN.homepage = "https://github.com/<owner>/vfox-{{.Name}}"
+--- Plugin license, please choose a correct license according to your needs.
+PLUGIN.license = "Apache 2.0"
+--- Plugin description
+PLUGIN.description = "{{.Name}} plugin for vfox"
+
+--- !!! OPTIONAL !!!
+-- minimum compatible vfox version
+PLUGIN.minRuntimeVersion = "{{.RuntimeVersion}}"
+-- Some things that need user to be attention!
+PLUGIN.notes = {}
+--- List legacy configuration filenames for determining the specified version of the tool.
+--- such as ".node-version", ".nvmrc", etc.
+PLUGIN.legacyFilenames = {}
+
+--- What the plugin may do. An empty table lets it only use its own directories, "*" makes it unrestricted.
+--- Users are asked to approve the permissions when adding the plugin.
+PLUGIN.permissions = {
+    --- Hosts the plugin may reach, "*.example.com" matches the subdomains of example.com.
+    network = { "example.com" },
+    --- Paths the plugin may read, besides the writable ones, its own directory and its install directory.
+    readable = {},
+    --- Paths the plugin may write, besides its install directory and temporary directories.
+    writable = {},
+    --- Whether the plugin may run processes.
+    exec = false,
+}
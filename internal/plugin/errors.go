@@ -25,3 +25,5 @@ import (
 var ErrNoResultProvide = errors.New("no result provided")
 var ErrNoVersionProvided = errors.New("no version number provided")
 var ErrPluginNotFound = errors.New("plugin not found")
+var ErrHookTimeout = errors.New("timed out")
+var ErrHookMemoryLimit = errors.New("exceeded the memory limit")
@@ -0,0 +1,105 @@
+/*
+ *
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ *
+ */
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/metrics"
+	"time"
+
+	"github.com/version-fox/vfox/internal/config"
+)
+
+const (
+	heapMetric          = "/memory/classes/heap/objects:bytes"
+	memoryCheckInterval = 50 * time.Millisecond
+)
+
+// hookLimits bounds the execution of the hooks of a plugin.
+type hookLimits struct {
+	plugin string
+	base   context.Context
+	config *config.Plugin
+}
+
+// start returns the context hook runs under, and a function to call when the hook returns, which
+// turns err into an error naming the plugin and the hook if the hook was stopped by a limit.
+func (h *hookLimits) start(hook string) (context.Context, func(err error) error) {
+	base := h.base
+	if base == nil {
+		base = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(base)
+	stopTimer := context.CancelFunc(func() {})
+	timeout := h.config.HookTimeout(hook)
+	if timeout > 0 {
+		ctx, stopTimer = context.WithTimeoutCause(ctx, timeout, ErrHookTimeout)
+	}
+	stopWatch := func() {}
+	if h.config.MemoryLimit > 0 {
+		stopWatch = watchMemory(uint64(h.config.MemoryLimit)<<20, cancel)
+	}
+	return ctx, func(err error) error {
+		stopWatch()
+		cause := context.Cause(ctx)
+		stopTimer()
+		cancel(nil)
+		if err == nil || cause == nil {
+			return err
+		}
+		switch {
+		case errors.Is(cause, ErrHookTimeout):
+			return fmt.Errorf("plugin %s: hook %s %w after %s", h.plugin, hook, ErrHookTimeout, timeout)
+		case errors.Is(cause, ErrHookMemoryLimit):
+			return fmt.Errorf("plugin %s: hook %s %w of %d MiB", h.plugin, hook, ErrHookMemoryLimit, h.config.MemoryLimit)
+		default:
+			return fmt.Errorf("plugin %s: hook %s cancelled: %w", h.plugin, hook, cause)
+		}
+	}
+}
+
+// watchMemory cancels the hook with ErrHookMemoryLimit once the heap grew by limit bytes.
+// Lua values live on the Go heap, so this measures the process, which is approximate but
+// needs no cooperation from the VM. The growth counts every goroutine: when hooks of several
+// plugins run in parallel, as in activate, the allocations of one can stop the hook of another.
+func watchMemory(limit uint64, cancel context.CancelCauseFunc) func() {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	baseline := sample[0].Value.Uint64()
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(memoryCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				metrics.Read(sample)
+				if heap := sample[0].Value.Uint64(); heap > baseline && heap-baseline > limit {
+					cancel(ErrHookMemoryLimit)
+					return
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,117 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package plugin_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/version-fox/vfox/internal"
+	"github.com/version-fox/vfox/internal/config"
+	"github.com/version-fox/vfox/internal/plugin"
+	"github.com/version-fox/vfox/internal/shared/cache"
+)
+
+const limitsPlugin = `
+PLUGIN = { name = "limits", version = "0.0.1", permissions = { network = { "127.0.0.1" } } }
+
+function PLUGIN:Available(ctx)
+    while true do end
+end
+
+function PLUGIN:PreInstall(ctx)
+    local http = require("http")
+    local resp, err = http.get({ url = "%s" })
+    if err ~= nil then
+        error(err)
+    end
+    return { version = "1.0.0" }
+end
+
+function PLUGIN:EnvKeys(ctx)
+    local t = {}
+    for i = 1, 100000000 do
+        t[i] = { i }
+    end
+    return {}
+end
+`
+
+func TestHookLimits(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.lua"), []byte(strings.Replace(limitsPlugin, "%s", server.URL, 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+	envCtx := *manager.RuntimeEnvContext
+	userConfig := *envCtx.UserConfig
+	userConfig.Plugin = &config.Plugin{
+		HookTimeouts: map[string]cache.Duration{
+			"available":  cache.Duration(100 * time.Millisecond),
+			"preInstall": cache.Duration(100 * time.Millisecond),
+			"envKeys":    0,
+		},
+		MemoryLimit: 16,
+	}
+	envCtx.UserConfig = &userConfig
+
+	plug, err := plugin.CreatePlugin(dir, &envCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plug.Close()
+
+	_, err = plug.Available(&plugin.AvailableHookCtx{})
+	if !errors.Is(err, plugin.ErrHookTimeout) || !strings.Contains(err.Error(), "plugin limits: hook Available") {
+		t.Errorf("expected Available to time out, got %v", err)
+	}
+
+	start := time.Now()
+	_, err = plug.PreInstall(&plugin.PreInstallHookCtx{Version: "1.0.0"})
+	if !errors.Is(err, plugin.ErrHookTimeout) {
+		t.Errorf("expected PreInstall to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the request to be cancelled, PreInstall took %s", elapsed)
+	}
+
+	_, err = plug.EnvKeys(&plugin.EnvKeysHookCtx{})
+	if !errors.Is(err, plugin.ErrHookMemoryLimit) || !strings.Contains(err.Error(), "plugin limits: hook EnvKeys") {
+		t.Errorf("expected EnvKeys to exceed the memory limit, got %v", err)
+	}
+}
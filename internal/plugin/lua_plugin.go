@@ -21,12 +21,16 @@ package plugin
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/version-fox/vfox/internal/config"
 	"github.com/version-fox/vfox/internal/env"
 	"github.com/version-fox/vfox/internal/plugin/luai"
 	"github.com/version-fox/vfox/internal/plugin/luai/codec"
 	"github.com/version-fox/vfox/internal/plugin/luai/module"
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
 	"github.com/version-fox/vfox/internal/shared/logger"
 	"github.com/version-fox/vfox/internal/shared/util"
 	lua "github.com/yuin/gopher-lua"
@@ -35,6 +39,8 @@ import (
 type LuaPlugin struct {
 	vm        *luai.LuaVM
 	pluginObj *lua.LTable
+	limits    *hookLimits
+	guard     *permission.Guard
 }
 
 func (l *LuaPlugin) HasFunction(name string) bool {
@@ -153,6 +159,7 @@ func (l *LuaPlugin) PostInstall(ctx *PostInstallHookCtx) error {
 
 func (l *LuaPlugin) ParseLegacyFile(ctx *ParseLegacyFileHookCtx) (*ParseLegacyFileResult, error) {
 	L := l.vm.Instance
+	l.guard.AllowRead(ctx.Filepath)
 	ctxTable, err := codec.Marshal(L, ctx)
 	if err != nil {
 		return nil, err
@@ -175,16 +182,25 @@ func (l *LuaPlugin) ParseLegacyFile(ctx *ParseLegacyFileHookCtx) (*ParseLegacyFi
 func (l *LuaPlugin) CallFunction(funcName string, args ...lua.LValue) (*lua.LTable, error) {
 	logger.Debugf("CallFunction: %s\n", funcName)
 
-	table, err := l.vm.CallFunction(l.pluginObj, funcName, args...)
+	ctx, finish := l.limits.start(funcName)
+	table, err := l.vm.CallFunction(ctx, l.pluginObj, funcName, args...)
 
-	return table, err
+	return table, finish(err)
 }
 
 func CreateLuaPlugin(pluginDirPath string, envCtx *env.RuntimeEnvContext) (*LuaPlugin, *Metadata, error) {
 	vm := luai.NewLuaVM()
-	if err := vm.Prepare(&module.PreloadOptions{
-		Config: envCtx.UserConfig,
-	}); err != nil {
+	guard := newGuard(pluginDirPath, envCtx)
+	options := &module.PreloadOptions{
+		Config:    envCtx.UserConfig,
+		Transport: envCtx.PluginTransport(),
+		Guard:     guard,
+		Name:      filepath.Base(pluginDirPath),
+	}
+	if envCtx.ShellOutput {
+		options.Stdout = os.Stderr
+	}
+	if err := vm.Prepare(options); err != nil {
 		return nil, nil, err
 	}
 
@@ -249,6 +265,16 @@ func CreateLuaPlugin(pluginDirPath string, envCtx *env.RuntimeEnvContext) (*LuaP
 	if err = codec.Unmarshal(PLUGIN, pluginInfo); err != nil {
 		return nil, nil, err
 	}
+	if declared, ok := PLUGIN.RawGetString("permissions").(lua.LString); ok {
+		if declared != permission.All {
+			return nil, nil, fmt.Errorf("invalid PLUGIN.permissions %q, expected a table or %q", declared, permission.All)
+		}
+		pluginInfo.Permissions = &permission.Permissions{Unrestricted: true}
+	}
+	if pluginInfo.Permissions == nil {
+		logger.Debugf("Plugin %s declares no PLUGIN.permissions and runs unrestricted, which is deprecated\n", pluginInfo.Name)
+	}
+	guard.Grant(pluginInfo.Permissions)
 
 	navigator, err := codec.Marshal(vm.Instance, codec.Navigator{
 		UserAgent: luai.ComputeUserAgent(envCtx.RuntimeVersion, pluginInfo.Name, pluginInfo.Version),
@@ -258,10 +284,36 @@ func CreateLuaPlugin(pluginDirPath string, envCtx *env.RuntimeEnvContext) (*LuaP
 	}
 	vm.Instance.SetGlobal(codec.NavigatorObjKey, navigator)
 
+	pluginConfig := config.EmptyPlugin
+	if envCtx.UserConfig != nil && envCtx.UserConfig.Plugin != nil {
+		pluginConfig = envCtx.UserConfig.Plugin
+	}
 	source := &LuaPlugin{
 		vm:        vm,
 		pluginObj: PLUGIN,
+		limits: &hookLimits{
+			plugin: pluginInfo.Name,
+			base:   envCtx.Context,
+			config: pluginConfig,
+		},
+		guard: guard,
 	}
 
 	return source, pluginInfo, nil
 }
+
+// newGuard creates the guard of a plugin, which may always read and write its install
+// directory, the temporary directories and its own directory.
+func newGuard(pluginDirPath string, envCtx *env.RuntimeEnvContext) *permission.Guard {
+	writable := []string{os.TempDir(), pluginDirPath}
+	if envCtx.PathMeta != nil {
+		name := strings.ToLower(filepath.Base(pluginDirPath))
+		writable = append(writable,
+			filepath.Join(envCtx.PathMeta.Shared.Installs, name),
+			// compatibility path introduced in v1.0.2
+			filepath.Join(envCtx.PathMeta.Shared.Installs, "cache", name),
+			envCtx.PathMeta.User.Temp,
+		)
+	}
+	return permission.NewGuard(writable...)
+}
@@ -1,14 +1,21 @@
 package archiver
 
 import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
 	"github.com/version-fox/vfox/internal/shared/util"
 	lua "github.com/yuin/gopher-lua"
 )
 
-// Preload adds strings to the given Lua state's package.preload table. After it
+// Preload adds archiver to the given Lua state's package.preload table. After it
 // has been preloaded, it can be loaded using require:
 //
-//	local strings = require("vfox.archiver")
+//	local archiver = require("vfox.archiver")
 func Preload(L *lua.LState) {
 	L.PreloadModule("vfox.archiver", Loader)
 }
@@ -23,6 +30,20 @@ func Loader(L *lua.LState) int {
 
 var api = map[string]lua.LGFunction{
 	"decompress": decompress,
+	"list":       list,
+	"open":       open,
+	"extract":    extract,
+	"compress":   compress,
+}
+
+var errUnsupported = errors.New("unsupported archive format")
+
+func decompressor(archiverPath string) (util.Decompressor, error) {
+	d := util.NewDecompressor(archiverPath)
+	if d == nil {
+		return nil, errUnsupported
+	}
+	return d, nil
 }
 
 // decompress lua archiver.decompress(sourceFile, targetPath): port of go string.decompress() returns error
@@ -30,7 +51,14 @@ func decompress(L *lua.LState) int {
 	archiverPath := L.CheckString(1)
 	targetPath := L.CheckString(2)
 
-	err := util.NewDecompressor(archiverPath).Decompress(targetPath)
+	if err := permission.Lookup(L).CheckWrite(targetPath); err != nil {
+		L.Push(lua.LString(err.Error()))
+		return 1
+	}
+	d, err := decompressor(archiverPath)
+	if err == nil {
+		err = d.Decompress(targetPath)
+	}
 	if err != nil {
 		L.Push(lua.LString(err.Error()))
 		return 1
@@ -39,3 +67,152 @@ func decompress(L *lua.LState) int {
 	}
 	return 1
 }
+
+// list lua archiver.list(archive) returns the entries of the archive, tables of name, size,
+// mode such as "0755", is_dir, is_symlink and link, or nil and an error. Names are paths
+// within the archive, including its root folder.
+func list(L *lua.LState) int {
+	archiverPath := L.CheckString(1)
+	d, err := decompressor(archiverPath)
+	if err != nil {
+		return pushError(L, err)
+	}
+	entries, err := d.List()
+	if err != nil {
+		return pushError(L, err)
+	}
+	result := L.CreateTable(len(entries), 0)
+	for _, entry := range entries {
+		t := L.NewTable()
+		t.RawSetString("name", lua.LString(entry.Name))
+		t.RawSetString("size", lua.LNumber(entry.Size))
+		t.RawSetString("mode", lua.LString("0"+strconv.FormatUint(uint64(entry.Mode.Perm()), 8)))
+		t.RawSetString("is_dir", lua.LBool(entry.Mode.IsDir()))
+		t.RawSetString("is_symlink", lua.LBool(entry.Mode&os.ModeSymlink != 0))
+		if entry.Link != "" {
+			t.RawSetString("link", lua.LString(entry.Link))
+		}
+		result.Append(t)
+	}
+	L.Push(result)
+	return 1
+}
+
+// open lua archiver.open(archive, entry) returns the content of the file entry of the archive,
+// named as by list, or nil and an error.
+func open(L *lua.LState) int {
+	archiverPath := L.CheckString(1)
+	name := L.CheckString(2)
+	d, err := decompressor(archiverPath)
+	if err != nil {
+		return pushError(L, err)
+	}
+	rc, err := d.Open(name)
+	if err != nil {
+		return pushError(L, err)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return pushError(L, err)
+	}
+	L.Push(lua.LString(content))
+	return 1
+}
+
+// extract lua archiver.extract(archive, entry, dest) writes the file entry of the archive,
+// named as by list, to the file dest. Returns error
+func extract(L *lua.LState) int {
+	archiverPath := L.CheckString(1)
+	name := L.CheckString(2)
+	dest := L.CheckString(3)
+	if err := permission.Lookup(L).CheckWrite(dest); err != nil {
+		L.Push(lua.LString(err.Error()))
+		return 1
+	}
+	if err := extractEntry(archiverPath, name, dest); err != nil {
+		L.Push(lua.LString(err.Error()))
+		return 1
+	}
+	L.Push(lua.LNil)
+	return 1
+}
+
+func extractEntry(archiverPath, name, dest string) error {
+	d, err := decompressor(archiverPath)
+	if err != nil {
+		return err
+	}
+	var mode os.FileMode = 0644
+	entries, err := d.List()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name == name && entry.Mode.Perm() != 0 {
+			mode = entry.Mode.Perm()
+		}
+	}
+	rc, err := d.Open(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err = os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, rc); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// compress lua archiver.compress(dir, dest, format) creates the archive dest of the directory,
+// stored under its base name, in format tar.gz, tar.xz, tar.zst or zip, or the format of
+// the extension of dest if format is nil. Returns error
+func compress(L *lua.LState) int {
+	dir := L.CheckString(1)
+	dest := L.CheckString(2)
+	format := L.OptString(3, "")
+	if err := permission.Lookup(L).CheckWrite(dest); err != nil {
+		L.Push(lua.LString(err.Error()))
+		return 1
+	}
+	if err := compressDir(dir, dest, format); err != nil {
+		L.Push(lua.LString(err.Error()))
+		return 1
+	}
+	L.Push(lua.LNil)
+	return 1
+}
+
+func compressDir(dir, dest, format string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.New(dir + " is not a directory")
+	}
+	c, err := util.NewCompressor(dest, format)
+	if err != nil {
+		return err
+	}
+	if err = c.AddDir(dir, filepath.Base(filepath.Clean(dir))); err != nil {
+		_ = c.Close()
+		_ = os.Remove(dest)
+		return err
+	}
+	return c.Close()
+}
+
+func pushError(L *lua.LState, err error) int {
+	L.Push(lua.LNil)
+	L.Push(lua.LString(err.Error()))
+	return 2
+}
@@ -33,3 +33,75 @@ func eval(str string, t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestListAndOpen(t *testing.T) {
+	const str = `
+	local archiver = require("vfox.archiver")
+	local entries, err = archiver.list("testdata/test.zip")
+	assert(err == nil, err)
+	local found = false
+	for _, entry in ipairs(entries) do
+		if entry.name == "test/test.txt" then
+			found = true
+			assert(entry.size == 4, "size")
+			assert(not entry.is_dir, "is_dir")
+		end
+	end
+	assert(found, "test/test.txt not listed")
+	local content, err = archiver.open("testdata/test.zip", "test/test.txt")
+	assert(err == nil, err)
+	assert(#content == 4, "content")
+	local content, err = archiver.open("testdata/test.zip", "test/missing.txt")
+	assert(content == nil and err ~= nil, "missing entry")
+	local entries, err = archiver.list("testdata/test.rar")
+	assert(entries == nil and err == "unsupported archive format", "unsupported format")
+	`
+	eval(str, t)
+}
+
+func TestCompress(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/sdk"
+	if err := os.MkdirAll(src+"/bin", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src+"/bin/run", []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{"zip", "tar.gz", "tar.xz", "tar.zst"} {
+		t.Run(format, func(t *testing.T) {
+			str := `
+			local archiver = require("vfox.archiver")
+			local dir, format = "` + dir + `", "` + format + `"
+			local archive = dir .. "/sdk." .. format
+			local err = archiver.compress(dir .. "/sdk", archive)
+			assert(err == nil, err)
+			local entries, err = archiver.list(archive)
+			assert(err == nil, err)
+			local found = false
+			for _, entry in ipairs(entries) do
+				if entry.name == "sdk/bin/run" then
+					found = true
+					assert(entry.mode == "0755", "mode " .. entry.mode)
+				end
+			end
+			assert(found, "sdk/bin/run not listed")
+			local content, err = archiver.open(archive, "sdk/bin/run")
+			assert(content == "#!/bin/sh\n", err)
+			local dest = dir .. "/" .. format .. "/run"
+			local err = archiver.extract(archive, "sdk/bin/run", dest)
+			assert(err == nil, err)
+			local f = io.open(dest, "r")
+			assert(f:read("*a") == "#!/bin/sh\n", "extracted content")
+			f:close()
+			`
+			eval(str, t)
+		})
+	}
+	str := `
+	local archiver = require("vfox.archiver")
+	local err = archiver.compress("` + src + `", "` + dir + `/sdk.rar")
+	assert(err == "unsupported archive format sdk.rar", err)
+	`
+	eval(str, t)
+}
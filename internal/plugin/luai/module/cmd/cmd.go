@@ -0,0 +1,204 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
+	"github.com/version-fox/vfox/internal/shared/logger"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// waitDelay is how long to wait for the output of a killed command, which
+	// may be held open by its children.
+	waitDelay = 2 * time.Second
+	// maxErrorOutput is how much of the end of stderr an exit error quotes.
+	maxErrorOutput = 1024
+)
+
+// Preload adds cmd to the given Lua state's package.preload table. After it
+// has been preloaded, it can be loaded using require:
+//
+//	local cmd = require("cmd")
+func Preload(L *lua.LState) {
+	L.PreloadModule("cmd", loader)
+}
+
+// loader is the module loader function.
+func loader(L *lua.LState) int {
+	t := L.NewTable()
+	L.SetFuncs(t, api)
+	L.Push(t)
+	return 1
+}
+
+var api = map[string]lua.LGFunction{
+	"exec": execute,
+}
+
+// execute lua cmd.exec(command, {cwd=, env=, timeout=}) runs command, a string run by the shell
+// or a table of the program and its arguments. It returns a table of stdout, stderr and code,
+// and an error if the command could not run or exited with a non-zero code.
+func execute(L *lua.LState) int {
+	name, args, display, err := commandLine(L.Get(1))
+	if err != nil {
+		L.ArgError(1, err.Error())
+		return 0
+	}
+	options := L.OptTable(2, L.NewTable())
+
+	if err = permission.Lookup(L).CheckExec(display); err != nil {
+		return pushError(L, nil, err)
+	}
+
+	ctx := L.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout := lua.LVAsNumber(options.RawGetString("timeout")); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(float64(timeout)*float64(time.Second)))
+		defer cancel()
+	}
+
+	c := exec.CommandContext(ctx, name, args...)
+	if cwd, ok := options.RawGetString("cwd").(lua.LString); ok {
+		c.Dir = string(cwd)
+	}
+	if env, ok := options.RawGetString("env").(*lua.LTable); ok {
+		c.Env = os.Environ()
+		env.ForEach(func(key, value lua.LValue) {
+			c.Env = append(c.Env, key.String()+"="+value.String())
+		})
+	}
+	c.WaitDelay = waitDelay
+	killProcessGroup(c)
+
+	stdout := &logWriter{stream: "stdout"}
+	stderr := &logWriter{stream: "stderr"}
+	c.Stdout, c.Stderr = stdout, stderr
+	logger.Debugf("[cmd] exec: %s (cwd: %s)\n", display, c.Dir)
+
+	err = c.Run()
+	stdout.flush()
+	stderr.flush()
+
+	result := L.NewTable()
+	result.RawSetString("stdout", lua.LString(stdout.buf.String()))
+	result.RawSetString("stderr", lua.LString(stderr.buf.String()))
+	result.RawSetString("code", lua.LNumber(c.ProcessState.ExitCode()))
+	if err == nil {
+		L.Push(result)
+		return 1
+	}
+	if ctx.Err() != nil {
+		err = fmt.Errorf("%s: %w", display, ctx.Err())
+	} else if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) {
+		err = fmt.Errorf("%s: exit code %d%s", display, exitErr.ExitCode(), quoteOutput(stderr.buf.String()))
+	} else {
+		err = fmt.Errorf("%s: %w", display, err)
+	}
+	if c.ProcessState == nil {
+		result = nil
+	}
+	return pushError(L, result, err)
+}
+
+// commandLine returns the program and arguments of command. A string is run by the shell.
+func commandLine(command lua.LValue) (name string, args []string, display string, err error) {
+	switch cmd := command.(type) {
+	case lua.LString:
+		if runtime.GOOS == "windows" {
+			return "cmd", []string{"/C", string(cmd)}, string(cmd), nil
+		}
+		return "sh", []string{"-c", string(cmd)}, string(cmd), nil
+	case *lua.LTable:
+		for i := 1; i <= cmd.Len(); i++ {
+			args = append(args, cmd.RawGetInt(i).String())
+		}
+		if len(args) == 0 {
+			return "", nil, "", errors.New("empty command")
+		}
+		return args[0], args[1:], strings.Join(args, " "), nil
+	}
+	return "", nil, "", errors.New("string or table expected")
+}
+
+func quoteOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return ""
+	}
+	if len(output) > maxErrorOutput {
+		output = "..." + output[len(output)-maxErrorOutput:]
+	}
+	return ": " + output
+}
+
+func pushError(L *lua.LState, result *lua.LTable, err error) int {
+	if result == nil {
+		L.Push(lua.LNil)
+	} else {
+		L.Push(result)
+	}
+	L.Push(lua.LString(err.Error()))
+	return 2
+}
+
+// logWriter keeps the output of a command and logs it line by line for --debug.
+type logWriter struct {
+	mu      sync.Mutex
+	stream  string
+	buf     bytes.Buffer
+	pending []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		logger.Debugf("[cmd] %s: %s\n", w.stream, strings.TrimRight(string(w.pending[:i]), "\r"))
+		w.pending = w.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *logWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) > 0 {
+		logger.Debugf("[cmd] %s: %s\n", w.stream, w.pending)
+		w.pending = nil
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	dir := t.TempDir()
+	str := `
+	local cmd = require("cmd")
+
+	local r, err = cmd.exec("echo out; echo err >&2; pwd; echo $VFOX_TEST", { cwd = "` + dir + `", env = { VFOX_TEST = "value" } })
+	assert(err == nil, err)
+	assert(r.code == 0, "code")
+	assert(r.stdout == "out\n` + dir + `\nvalue\n", "stdout: " .. r.stdout)
+	assert(r.stderr == "err\n", "stderr: " .. r.stderr)
+
+	r, err = cmd.exec({ "printf", "%s", "a b" })
+	assert(err == nil, err)
+	assert(r.stdout == "a b", "argv stdout: " .. r.stdout)
+
+	r, err = cmd.exec("echo failed >&2; exit 3")
+	assert(r.code == 3, "exit code")
+	assert(err == "echo failed >&2; exit 3: exit code 3: failed", "exit error: " .. tostring(err))
+
+	r, err = cmd.exec({ "vfox-no-such-command" })
+	assert(r == nil and err ~= nil, "missing command")
+
+	r, err = cmd.exec("sleep 10", { timeout = 0.1 })
+	assert(err ~= nil and string.find(err, "deadline exceeded"), "timeout: " .. tostring(err))
+	`
+	eval(t, str, nil)
+}
+
+func TestExecCancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	s := lua.NewState()
+	defer s.Close()
+	Preload(s)
+	s.SetContext(ctx)
+	start := time.Now()
+	err := s.DoString(`
+	local r, err = require("cmd").exec("sleep 10 & sleep 10; wait")
+	assert(err == nil, err)
+	`)
+	if err == nil {
+		t.Fatal("expected the command to be cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the command and its children to be killed, took %s", elapsed)
+	}
+}
+
+func TestExecPermission(t *testing.T) {
+	guard := permission.NewGuard()
+	guard.Grant(&permission.Permissions{})
+	eval(t, `
+	local r, err = require("cmd").exec("echo hi")
+	assert(r == nil, "expected no result")
+	assert(string.find(err, "permission denied"), err)
+	`, guard)
+}
+
+func eval(t *testing.T, str string, guard *permission.Guard) {
+	s := lua.NewState()
+	defer s.Close()
+	if guard != nil {
+		permission.Attach(s, guard)
+		defer permission.Detach(s)
+	}
+
+	Preload(s)
+	if err := s.DoString(str); err != nil {
+		t.Error(strings.TrimSpace(err.Error()))
+	}
+}
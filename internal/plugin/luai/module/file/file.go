@@ -14,48 +14,271 @@
  *    limitations under the License.
  */
 
-package html
+package file
 
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
+	"github.com/version-fox/vfox/internal/shared/util"
 	lua "github.com/yuin/gopher-lua"
 )
 
-const luaFileTypeName = "file_operation"
+// Preload adds file to the given Lua state's package.preload table. After it
+// has been preloaded, it can be loaded using require:
+//
+//	local file = require("file")
+//
+// Every path is confined to the install, plugin and temporary directories, and the
+// paths the plugin declares writable.
+func Preload(L *lua.LState) {
+	L.PreloadModule("file", loader)
+}
+
+// loader is the module loader function.
+func loader(L *lua.LState) int {
+	t := L.NewTable()
+	L.SetFuncs(t, api)
+	L.Push(t)
+	return 1
+}
 
-type FileOperation struct {
-	rootPath string
+var api = map[string]lua.LGFunction{
+	"read":     read,
+	"write":    write,
+	"append":   appendFile,
+	"exists":   exists,
+	"stat":     stat,
+	"mkdir":    mkdir,
+	"remove":   remove,
+	"copy":     copyPath,
+	"move":     move,
+	"glob":     glob,
+	"chmod":    chmod,
+	"readlink": readlink,
+	"symlink":  symlink,
 }
 
-func (f *FileOperation) symlink(L *lua.LState) int {
+// read lua file.read(path) returns the content of the file, or nil and an error.
+func read(L *lua.LState) int {
+	path := L.CheckString(1)
+	if err := confine(L, path); err != nil {
+		return pushError(L, err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return pushError(L, err)
+	}
+	L.Push(lua.LString(content))
+	return 1
+}
+
+// write lua file.write(path, content) replaces the content of the file, returns true or nil and an error.
+func write(L *lua.LState) int {
+	return writeFile(L, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+}
+
+// appendFile lua file.append(path, content) appends to the file, returns true or nil and an error.
+func appendFile(L *lua.LState) int {
+	return writeFile(L, os.O_WRONLY|os.O_CREATE|os.O_APPEND)
+}
+
+func writeFile(L *lua.LState, flag int) int {
+	path := L.CheckString(1)
+	content := L.CheckString(2)
+	if err := confine(L, path); err != nil {
+		return pushError(L, err)
+	}
+	f, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return pushError(L, err)
+	}
+	_, err = f.WriteString(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return pushResult(L, err)
+}
+
+// exists lua file.exists(path) returns whether the path exists, or false and an error
+// if the path is not allowed.
+func exists(L *lua.LState) int {
+	path := L.CheckString(1)
+	if err := confine(L, path); err != nil {
+		L.Push(lua.LFalse)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+	_, err := os.Lstat(path)
+	L.Push(lua.LBool(err == nil))
+	return 1
+}
+
+// stat lua file.stat(path) returns a table of name, size, mode such as "0755", mod_time in
+// seconds, is_dir and is_symlink, or nil and an error.
+func stat(L *lua.LState) int {
+	path := L.CheckString(1)
+	if err := confine(L, path); err != nil {
+		return pushError(L, err)
+	}
+	linfo, err := os.Lstat(path)
+	if err != nil {
+		return pushError(L, err)
+	}
+	info := linfo
+	if linfo.Mode()&os.ModeSymlink != 0 {
+		if target, err := os.Stat(path); err == nil {
+			info = target
+		}
+	}
+	t := L.NewTable()
+	t.RawSetString("name", lua.LString(info.Name()))
+	t.RawSetString("size", lua.LNumber(info.Size()))
+	t.RawSetString("mode", lua.LString("0"+strconv.FormatUint(uint64(info.Mode().Perm()), 8)))
+	t.RawSetString("mod_time", lua.LNumber(info.ModTime().Unix()))
+	t.RawSetString("is_dir", lua.LBool(info.IsDir()))
+	t.RawSetString("is_symlink", lua.LBool(linfo.Mode()&os.ModeSymlink != 0))
+	L.Push(t)
+	return 1
+}
+
+// mkdir lua file.mkdir(path) creates the directory and its parents, returns true or nil and an error.
+func mkdir(L *lua.LState) int {
+	path := L.CheckString(1)
+	if err := confine(L, path); err != nil {
+		return pushError(L, err)
+	}
+	return pushResult(L, os.MkdirAll(path, 0755))
+}
+
+// remove lua file.remove(path) removes the file, or the directory and its content,
+// returns true or nil and an error. Removing a missing path succeeds.
+func remove(L *lua.LState) int {
+	path := L.CheckString(1)
+	if err := confine(L, path); err != nil {
+		return pushError(L, err)
+	}
+	return pushResult(L, os.RemoveAll(path))
+}
+
+// copyPath lua file.copy(src, dest) copies the file or directory, returns true or nil and an error.
+func copyPath(L *lua.LState) int {
+	src := L.CheckString(1)
+	dest := L.CheckString(2)
+	if err := confine(L, src, dest); err != nil {
+		return pushError(L, err)
+	}
+	return pushResult(L, util.CopyPath(src, dest))
+}
+
+// move lua file.move(src, dest) moves the file or directory, returns true or nil and an error.
+func move(L *lua.LState) int {
 	src := L.CheckString(1)
 	dest := L.CheckString(2)
-	// TODO check
-	err := os.Symlink(filepath.Join(f.rootPath, src), filepath.Join(f.rootPath, dest))
+	if err := confine(L, src, dest); err != nil {
+		return pushError(L, err)
+	}
+	return pushResult(L, util.MovePath(src, dest))
+}
+
+// glob lua file.glob(pattern) returns a table of the paths matching the pattern, in the
+// syntax of filepath.Match, or nil and an error.
+func glob(L *lua.LState) int {
+	pattern := L.CheckString(1)
+	base := pattern
+	if i := strings.IndexAny(pattern, `*?[`); i >= 0 {
+		base = filepath.Dir(pattern[:i])
+	}
+	if err := confine(L, base); err != nil {
+		return pushError(L, err)
+	}
+	matches, err := filepath.Glob(pattern)
 	if err != nil {
-		L.RaiseError("%s", err.Error())
+		return pushError(L, err)
+	}
+	t := L.CreateTable(len(matches), 0)
+	for _, match := range matches {
+		// a match may be reached through a symbolic link leading out of the allowed paths
+		if confine(L, match) == nil {
+			t.Append(lua.LString(match))
+		}
+	}
+	L.Push(t)
+	return 1
+}
+
+// chmod lua file.chmod(path, mode) sets the mode of the file, an octal string such as "755",
+// or "+x" to make it executable by whoever may read it. Returns true or nil and an error.
+func chmod(L *lua.LState) int {
+	path := L.CheckString(1)
+	mode := L.CheckString(2)
+	if err := confine(L, path); err != nil {
+		return pushError(L, err)
+	}
+	if mode == "+x" {
+		info, err := os.Stat(path)
+		if err != nil {
+			return pushError(L, err)
+		}
+		perm := info.Mode().Perm()
+		return pushResult(L, os.Chmod(path, perm|(perm&0444)>>2))
+	}
+	perm, err := strconv.ParseUint(strings.TrimPrefix(mode, "0o"), 8, 32)
+	if err != nil || perm > 0777 {
+		L.ArgError(2, "octal mode or +x expected")
 		return 0
 	}
-	L.Push(lua.LTrue)
+	return pushResult(L, os.Chmod(path, os.FileMode(perm)))
+}
+
+// readlink lua file.readlink(path) returns the target of the symbolic link, or nil and an error.
+func readlink(L *lua.LState) int {
+	path := L.CheckString(1)
+	if err := confine(L, path); err != nil {
+		return pushError(L, err)
+	}
+	target, err := os.Readlink(path)
+	if err != nil {
+		return pushError(L, err)
+	}
+	L.Push(lua.LString(target))
 	return 1
 }
 
-func (f *FileOperation) luaMap() map[string]lua.LGFunction {
-	return map[string]lua.LGFunction{
-		"symlink": f.symlink,
+// symlink lua file.symlink(target, link) creates link pointing to target, returns true or nil and an error.
+func symlink(L *lua.LState) int {
+	target := L.CheckString(1)
+	link := L.CheckString(2)
+	if err := confine(L, link); err != nil {
+		return pushError(L, err)
 	}
+	return pushResult(L, util.MkSymlink(target, link))
 }
 
-func (f *FileOperation) loader(L *lua.LState) int {
-	t := L.NewTable()
-	L.SetFuncs(t, f.luaMap())
-	L.Push(t)
+// confine returns an error unless every path is within the allowed paths.
+func confine(L *lua.LState, paths ...string) error {
+	guard := permission.Lookup(L)
+	for _, path := range paths {
+		if err := guard.CheckConfined(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pushResult(L *lua.LState, err error) int {
+	if err != nil {
+		return pushError(L, err)
+	}
+	L.Push(lua.LTrue)
 	return 1
 }
 
-func Preload(L *lua.LState, rootPath string) {
-	operation := &FileOperation{rootPath: rootPath}
-	L.PreloadModule("file", operation.loader)
+func pushError(L *lua.LState, err error) int {
+	L.Push(lua.LNil)
+	L.Push(lua.LString(err.Error()))
+	return 2
 }
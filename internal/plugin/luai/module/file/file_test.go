@@ -14,11 +14,15 @@
  *    limitations under the License.
  */
 
-package html
+package file
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
 	lua "github.com/yuin/gopher-lua"
 )
 
@@ -27,14 +31,93 @@ func TestRequire(t *testing.T) {
 	local file = require("file")
 	assert(type(file) == "table")
 	assert(type(file.symlink) == "function")
+	assert(type(file.read) == "function")
 	`
-	evalLua(str, t)
+	evalLua(str, t, nil)
 }
 
-func evalLua(str string, t *testing.T) {
+func TestFile(t *testing.T) {
+	root := filepath.ToSlash(t.TempDir())
+	outside := filepath.ToSlash(t.TempDir())
+	if err := os.WriteFile(outside+"/secret", []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	guard := permission.NewGuard(root)
+	guard.Grant(&permission.Permissions{})
+	str := `
+	local file = require("file")
+	local root = "` + root + `"
+
+	assert(file.mkdir(root .. "/a/b"), "file.mkdir()")
+	assert(file.write(root .. "/a/b/version.txt", "1.0"), "file.write()")
+	assert(file.append(root .. "/a/b/version.txt", ".1\n"), "file.append()")
+	assert(file.read(root .. "/a/b/version.txt") == "1.0.1\n", "file.read()")
+	local _, err = file.read(root .. "/missing")
+	assert(err ~= nil, "file.read() missing")
+
+	assert(file.exists(root .. "/a/b"), "file.exists()")
+	assert(not file.exists(root .. "/missing"), "file.exists() missing")
+	local info = file.stat(root .. "/a/b/version.txt")
+	assert(info.name == "version.txt" and info.size == 6 and not info.is_dir, "file.stat()")
+
+	assert(file.copy(root .. "/a", root .. "/c"), "file.copy()")
+	assert(file.read(root .. "/c/b/version.txt") == "1.0.1\n", "file.copy() content")
+	assert(file.move(root .. "/c", root .. "/d"), "file.move()")
+	assert(not file.exists(root .. "/c") and file.exists(root .. "/d/b/version.txt"), "file.move() paths")
+
+	local matches = file.glob(root .. "/*/b/*.txt")
+	assert(#matches == 2, "file.glob(): " .. #matches)
+
+	assert(file.remove(root .. "/d"), "file.remove()")
+	assert(not file.exists(root .. "/d"), "file.remove() path")
+
+	local _, err = file.write("` + filepath.ToSlash(t.TempDir()) + `/outside", "x")
+	assert(err ~= nil and string.find(err, "permission denied"), "file.write() outside: " .. tostring(err))
+	local _, err = file.move(root .. "/a", "` + filepath.ToSlash(t.TempDir()) + `/outside")
+	assert(err ~= nil, "file.move() outside")
+	local outside = "` + outside + `"
+	for name, fn in pairs({ read = file.read, stat = file.stat, readlink = file.readlink, glob = file.glob }) do
+		local result, err = fn(outside .. "/secret")
+		assert(result == nil and string.find(err, "permission denied"), "file." .. name .. "() outside: " .. tostring(err))
+	end
+	local _, err = file.copy(outside .. "/secret", root .. "/secret")
+	assert(err ~= nil and not file.exists(root .. "/secret"), "file.copy() from outside")
+	local found, err = file.exists(outside .. "/secret")
+	assert(not found and err ~= nil, "file.exists() outside")
+	`
+	evalLua(str, t, guard)
+}
+
+func TestFileUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses unix modes and symlinks")
+	}
+	root := t.TempDir()
+	str := `
+	local file = require("file")
+	local root = "` + root + `"
+
+	assert(file.write(root .. "/tool", "#!/bin/sh"), "file.write()")
+	assert(file.chmod(root .. "/tool", "600"), "file.chmod()")
+	assert(file.stat(root .. "/tool").mode == "0600", "file.chmod() mode")
+	assert(file.chmod(root .. "/tool", "+x"), "file.chmod(+x)")
+	assert(file.stat(root .. "/tool").mode == "0700", "file.chmod(+x) mode: " .. file.stat(root .. "/tool").mode)
+
+	assert(file.symlink(root .. "/tool", root .. "/link"), "file.symlink()")
+	assert(file.readlink(root .. "/link") == root .. "/tool", "file.readlink()")
+	assert(file.stat(root .. "/link").is_symlink, "file.stat() symlink")
+	`
+	evalLua(str, t, nil)
+}
+
+func evalLua(str string, t *testing.T, guard *permission.Guard) {
 	s := lua.NewState()
 	defer s.Close()
-	Preload(s, "")
+	if guard != nil {
+		permission.Attach(s, guard)
+		defer permission.Detach(s)
+	}
+	Preload(s)
 	if err := s.DoString(str); err != nil {
 		t.Error(err)
 	}
@@ -17,126 +17,252 @@
 package http
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/schollz/progressbar/v3"
 	"github.com/version-fox/vfox/internal/config"
 	"github.com/version-fox/vfox/internal/plugin/luai/codec"
+	"github.com/version-fox/vfox/internal/plugin/luai/module/json"
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
+	"github.com/version-fox/vfox/internal/shared/httpcache"
 	lua "github.com/yuin/gopher-lua"
 )
 
+// maxRetryDelay caps the backoff and the delays servers ask for in Retry-After.
+const maxRetryDelay = 30 * time.Second
+
+// retryBackoff is the delay before the first retry, doubled for every further retry.
+// It is a variable so that tests need not wait.
+var retryBackoff = 500 * time.Millisecond
+
+var errChecksumMismatch = errors.New("checksum mismatch")
+
 type Module struct {
 	proxy  *config.Proxy
 	client *http.Client
 }
 
-// Get performs a http get request
-// @param url string
-// @param headers table
-// @return resp table
-// @return err string
-// local http = require("http")
+// requestOptions are the options shared by all requests:
 //
-//	http.get({
-//	    url = "https://httpbin.org/json"
-//	}) return (response, error)
-//
-//	response : {
-//	    body = "",
-//	    status_code = 200,
-//	    headers = table
+//	{
+//	    method = "POST",
+//	    url = "https://example.com",
+//	    headers = { ["Accept"] = "application/json" },
+//	    body = "text",    -- or json = { key = "value" }, encoded with Content-Type application/json
+//	    timeout = 10,     -- seconds per attempt
+//	    retries = 2,      -- retries on network errors, 5xx and 429 responses
 //	}
-func (m *Module) Get(L *lua.LState) int {
-	param := L.CheckTable(1)
+type requestOptions struct {
+	method  string
+	url     string
+	headers http.Header
+	body    []byte
+	timeout time.Duration
+	retries int
+	// bypassCache sends the request past the HTTP cache, see httpcache.Bypass.
+	bypassCache bool
+}
+
+func parseOptions(param *lua.LTable, method string) (*requestOptions, error) {
 	urlStr := param.RawGetString("url")
 	if urlStr == lua.LNil {
-		L.Push(lua.LNil)
-		L.Push(lua.LString("url is required"))
-		return 2
+		return nil, errors.New("url is required")
 	}
-
-	req, err := http.NewRequest("GET", urlStr.String(), nil)
-	if err != nil {
-		L.Push(lua.LNil)
-		L.Push(lua.LString(err.Error()))
-		return 2
+	opts := &requestOptions{
+		method:  method,
+		url:     urlStr.String(),
+		headers: make(http.Header),
+	}
+	if opts.method == "" {
+		opts.method = http.MethodGet
+		if m, ok := param.RawGetString("method").(lua.LString); ok && m != "" {
+			opts.method = strings.ToUpper(string(m))
+		}
+	}
+	if table, ok := param.RawGetString("headers").(*lua.LTable); ok {
+		table.ForEach(func(key lua.LValue, value lua.LValue) {
+			opts.headers.Add(key.String(), value.String())
+		})
 	}
+	if value := param.RawGetString("json"); value != lua.LNil {
+		data, err := json.Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("encode json: %w", err)
+		}
+		opts.body = data
+		if opts.headers.Get("Content-Type") == "" {
+			opts.headers.Set("Content-Type", "application/json")
+		}
+	} else if body := param.RawGetString("body"); body != lua.LNil {
+		opts.body = []byte(body.String())
+	}
+	if timeout := lua.LVAsNumber(param.RawGetString("timeout")); timeout > 0 {
+		opts.timeout = time.Duration(float64(timeout) * float64(time.Second))
+	}
+	if retries := int(lua.LVAsNumber(param.RawGetString("retries"))); retries > 0 {
+		opts.retries = retries
+	}
+	return opts, nil
+}
 
-	headersTable := param.RawGetString("headers")
-	if headersTable != lua.LNil {
-		if table, ok := headersTable.(*lua.LTable); ok {
-			table.ForEach(func(key lua.LValue, value lua.LValue) {
-				req.Header.Add(key.String(), value.String())
-			})
+// do sends the request, retrying on network errors, 5xx and 429 responses. The response
+// body must be closed, which also releases the timeout of the attempt.
+func (m *Module) do(L *lua.LState, opts *requestOptions, prepare func(req *http.Request)) (*http.Response, error) {
+	ctx := requestContext(L)
+	if opts.bypassCache {
+		ctx = httpcache.Bypass(ctx)
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := m.send(L, ctx, opts, prepare)
+		if attempt >= opts.retries || !retryable(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+		delay := retryDelay(resp, attempt)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		case <-time.After(delay):
 		}
 	}
-	m.ensureUserAgent(L, req)
+}
 
-	resp, err := m.client.Do(req)
+func (m *Module) send(L *lua.LState, ctx context.Context, opts *requestOptions, prepare func(req *http.Request)) (*http.Response, error) {
+	cancel := context.CancelFunc(func() {})
+	if opts.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+	}
+	var body io.Reader
+	if opts.body != nil {
+		body = bytes.NewReader(opts.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, opts.method, opts.url, body)
 	if err != nil {
-		L.Push(lua.LNil)
-		L.Push(lua.LString(err.Error()))
-		return 2
+		cancel()
+		return nil, err
 	}
-	defer resp.Body.Close()
-	headers := L.NewTable()
-	for k, v := range resp.Header {
-		if len(v) > 0 {
-			headers.RawSetString(k, lua.LString(v[0]))
-		}
+	for key, values := range opts.headers {
+		req.Header[key] = append([]string(nil), values...)
 	}
-	body, err := io.ReadAll(resp.Body)
+	if prepare != nil {
+		prepare(req)
+	}
+	m.ensureUserAgent(L, req)
+	resp, err := m.client.Do(req)
 	if err != nil {
-		L.Push(lua.LNil)
-		L.Push(lua.LString(err.Error()))
-		return 2
+		cancel()
+		return nil, err
 	}
-	result := L.NewTable()
-	L.SetField(result, "body", lua.LString(body))
-	L.SetField(result, "status_code", lua.LNumber(resp.StatusCode))
-	L.SetField(result, "headers", headers)
-	L.SetField(result, "content_length", lua.LNumber(resp.ContentLength))
-	L.Push(result)
-	return 1
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
 }
 
-func (m *Module) Head(L *lua.LState) int {
-	param := L.CheckTable(1)
-	urlStr := param.RawGetString("url")
-	if urlStr == lua.LNil {
-		L.Push(lua.LNil)
-		L.Push(lua.LString("url is required"))
-		return 2
-	}
+// cancelBody releases the timeout of a request once its body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
 
-	req, err := http.NewRequest("HEAD", urlStr.String(), nil)
+func retryable(resp *http.Response, err error) bool {
 	if err != nil {
-		L.Push(lua.LNil)
-		L.Push(lua.LString(err.Error()))
-		return 2
-	}
-	headersTable := param.RawGetString("headers")
-	if headersTable != lua.LNil {
-		if table, ok := headersTable.(*lua.LTable); ok {
-			table.ForEach(func(key lua.LValue, value lua.LValue) {
-				req.Header.Add(key.String(), value.String())
-			})
+		return !errors.Is(err, permission.ErrDenied)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// retryDelay doubles the backoff for every attempt, unless the server asks for a delay in Retry-After.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, maxRetryDelay)
 		}
 	}
-	m.ensureUserAgent(L, req)
+	return min(retryBackoff<<attempt, maxRetryDelay)
+}
 
-	resp, err := m.client.Do(req)
+// Request performs a http request with any method.
+// @param options table, see requestOptions
+// @return resp table
+// @return err string
+// local http = require("http")
+//
+//	http.request({
+//	    method = "POST",
+//	    url = "https://httpbin.org/post",
+//	    json = { name = "vfox" },
+//	    retries = 2,
+//	}) return (response, error)
+//
+//	response : {
+//	    body = "",
+//	    status_code = 200,
+//	    status = "200 OK",
+//	    headers = table,
+//	    content_length = 0,
+//	    json = function, decodes the body
+//	}
+func (m *Module) Request(L *lua.LState) int {
+	return m.request(L, "")
+}
+
+// Get performs a http get request, see Request.
+func (m *Module) Get(L *lua.LState) int {
+	return m.request(L, http.MethodGet)
+}
+
+// Post performs a http post request, see Request.
+func (m *Module) Post(L *lua.LState) int {
+	return m.request(L, http.MethodPost)
+}
+
+// Put performs a http put request, see Request.
+func (m *Module) Put(L *lua.LState) int {
+	return m.request(L, http.MethodPut)
+}
+
+// Head performs a http head request, see Request. The response has no body.
+func (m *Module) Head(L *lua.LState) int {
+	return m.request(L, http.MethodHead)
+}
+
+func (m *Module) request(L *lua.LState, method string) int {
+	opts, err := parseOptions(L.CheckTable(1), method)
 	if err != nil {
-		L.Push(lua.LNil)
-		L.Push(lua.LString(err.Error()))
-		return 2
+		return pushError(L, err)
+	}
+	resp, err := m.do(L, opts, nil)
+	if err != nil {
+		return pushError(L, err)
 	}
 	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return pushError(L, err)
+	}
 
 	headers := L.NewTable()
 	for k, v := range resp.Header {
@@ -145,22 +271,43 @@ func (m *Module) Head(L *lua.LState) int {
 		}
 	}
 	result := L.NewTable()
+	if opts.method != http.MethodHead {
+		L.SetField(result, "body", lua.LString(body))
+		L.SetField(result, "json", L.NewFunction(func(L *lua.LState) int {
+			value, err := json.Decode(L, body)
+			if err != nil {
+				return pushError(L, err)
+			}
+			L.Push(value)
+			return 1
+		}))
+	}
 	L.SetField(result, "status_code", lua.LNumber(resp.StatusCode))
+	L.SetField(result, "status", lua.LString(resp.Status))
 	L.SetField(result, "headers", headers)
 	L.SetField(result, "content_length", lua.LNumber(resp.ContentLength))
 	L.Push(result)
 	return 1
 }
 
+func pushError(L *lua.LState, err error) int {
+	L.Push(lua.LNil)
+	L.Push(lua.LString(err.Error()))
+	return 2
+}
+
 // DownloadFile performs a http get request to write stream to a file.
-// @param url string
-// @param headers table
+// @param options table, see requestOptions, and
+// @param options.checksum string, "sha256:<hex>", also sha512, sha1 or md5, verified after the download
+// @param options.resume boolean, continue a partial download at the path
+// @param path string
 // @return err string
 // local http = require("http")
 //
 //		http.download_file({
 //		    url = "http://ip.jsontest.com/"
-//	     headers = {}
+//	     headers = {},
+//	     checksum = "sha256:...",
 //		}, "/usr/path/file/") return error
 func (m *Module) DownloadFile(L *lua.LState) int {
 	param := L.CheckTable(1)
@@ -169,50 +316,86 @@ func (m *Module) DownloadFile(L *lua.LState) int {
 		L.Push(lua.LString("filepath is required"))
 		return 1
 	}
-	urlStr := param.RawGetString("url")
-	if urlStr == lua.LNil {
-		L.Push(lua.LString("url is required"))
+	if err := permission.Lookup(L).CheckWrite(fp); err != nil {
+		L.Push(lua.LString(err.Error()))
 		return 1
 	}
-
-	req, err := http.NewRequest("GET", urlStr.String(), nil)
+	opts, err := parseOptions(param, http.MethodGet)
 	if err != nil {
 		L.Push(lua.LString(err.Error()))
 		return 1
 	}
-	headersTable := param.RawGetString("headers")
-	if headersTable != lua.LNil {
-		if table, ok := headersTable.(*lua.LTable); ok {
-			table.ForEach(func(key lua.LValue, value lua.LValue) {
-				req.Header.Add(key.String(), value.String())
-			})
+	opts.bypassCache = true
+	var expected *checksum
+	if value, ok := param.RawGetString("checksum").(lua.LString); ok && value != "" {
+		if expected, err = parseChecksum(string(value)); err != nil {
+			L.Push(lua.LString(err.Error()))
+			return 1
 		}
 	}
-	m.ensureUserAgent(L, req)
-	resp, err := m.client.Do(req)
-	if err != nil {
+	resume := lua.LVAsBool(param.RawGetString("resume"))
+
+	if err = m.download(L, opts, fp, expected, resume); err != nil {
 		L.Push(lua.LString(err.Error()))
 		return 1
 	}
+	return 0
+}
+
+func (m *Module) download(L *lua.LState, opts *requestOptions, fp string, expected *checksum, resume bool) error {
+	var offset int64
+	if resume {
+		if info, err := os.Stat(fp); err == nil && info.Mode().IsRegular() {
+			offset = info.Size()
+		}
+	}
+	resp, err := m.do(L, opts, func(req *http.Request) {
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+	})
+	if err != nil {
+		return err
+	}
 	defer resp.Body.Close()
-	if resp.StatusCode == http.StatusNotFound {
-		L.Push(lua.LString("file not found"))
-		return 1
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errors.New("file not found")
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The partial file is already complete.
+		return expected.verifyFile(fp)
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		flags = os.O_WRONLY | os.O_APPEND
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("%s: status code %d", opts.url, resp.StatusCode)
+	default:
+		offset = 0
+	}
+
+	hash := expected.newHash()
+	if offset > 0 {
+		if err = hashFile(hash, fp); err != nil {
+			return err
+		}
 	}
-	out, err := os.Create(fp)
+	out, err := os.OpenFile(fp, flags, 0644)
 	if err != nil {
-		L.Push(lua.LString(err.Error()))
-		return 1
+		return err
 	}
 	defer out.Close()
 
 	desc := "Downloading..."
-	if filepath.Ext(urlStr.String()) != "" {
-		desc = filepath.Base(urlStr.String())
+	if filepath.Ext(opts.url) != "" {
+		desc = filepath.Base(opts.url)
+	}
+	total := resp.ContentLength
+	if total >= 0 {
+		total += offset
 	}
-
 	bar := progressbar.NewOptions64(
-		resp.ContentLength,
+		total,
 		progressbar.OptionSetWriter(os.Stderr),
 		progressbar.OptionEnableColorCodes(true),
 		progressbar.OptionShowBytes(true),
@@ -230,26 +413,120 @@ func (m *Module) DownloadFile(L *lua.LState) int {
 		}),
 	)
 	defer bar.Close()
-	_, err = io.Copy(io.MultiWriter(out, bar), resp.Body)
+	_ = bar.Set64(offset)
+	if _, err = io.Copy(io.MultiWriter(out, bar, hash), resp.Body); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	if err = expected.verify(hash); err != nil {
+		_ = os.Remove(fp)
+		return err
+	}
+	return nil
+}
+
+// checksum is the expected digest of a download, "<algorithm>:<hex>" or a sha256 in hex.
+type checksum struct {
+	algorithm string
+	digest    string
+}
+
+func parseChecksum(value string) (*checksum, error) {
+	algorithm, digest, found := strings.Cut(value, ":")
+	if !found {
+		algorithm, digest = "sha256", value
+	}
+	c := &checksum{algorithm: strings.ToLower(algorithm), digest: strings.ToLower(digest)}
+	if c.newHash() == nil {
+		return nil, fmt.Errorf("unsupported checksum algorithm %s", algorithm)
+	}
+	if _, err := hex.DecodeString(c.digest); err != nil {
+		return nil, fmt.Errorf("invalid checksum %s", value)
+	}
+	return c, nil
+}
+
+// newHash returns the hash of the algorithm, or a hash discarding its input if c is nil.
+func (c *checksum) newHash() hash.Hash {
+	if c == nil {
+		return discardHash{}
+	}
+	switch c.algorithm {
+	case "sha256":
+		return sha256.New()
+	case "sha512":
+		return sha512.New()
+	case "sha1":
+		return sha1.New()
+	case "md5":
+		return md5.New()
+	}
+	return nil
+}
+
+func (c *checksum) verify(h hash.Hash) error {
+	if c == nil {
+		return nil
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != c.digest {
+		return fmt.Errorf("%w: expected %s %s, got %s", errChecksumMismatch, c.algorithm, c.digest, actual)
+	}
+	return nil
+}
+
+func (c *checksum) verifyFile(fp string) error {
+	if c == nil {
+		return nil
+	}
+	h := c.newHash()
+	if err := hashFile(h, fp); err != nil {
+		return err
+	}
+	if err := c.verify(h); err != nil {
+		_ = os.Remove(fp)
+		return err
+	}
+	return nil
+}
+
+func hashFile(h hash.Hash, fp string) error {
+	f, err := os.Open(fp)
 	if err != nil {
-		L.Push(lua.LString(err.Error()))
-		return 1
+		return err
 	}
-	return 0
+	defer f.Close()
+	_, err = io.Copy(h, f)
+	return err
 }
 
+// discardHash is used when no checksum is expected.
+type discardHash struct{}
+
+func (discardHash) Write(p []byte) (int, error) { return len(p), nil }
+func (discardHash) Sum(b []byte) []byte         { return b }
+func (discardHash) Reset()                      {}
+func (discardHash) Size() int                   { return 0 }
+func (discardHash) BlockSize() int              { return 1 }
+
 func (m *Module) luaMap() map[string]lua.LGFunction {
 	return map[string]lua.LGFunction{
+		"request":       m.Request,
 		"get":           m.Get,
 		"head":          m.Head,
+		"post":          m.Post,
+		"put":           m.Put,
 		"download_file": m.DownloadFile,
 	}
 }
 
-func createModule(proxy *config.Proxy) lua.LGFunction {
+func createModule(proxy *config.Proxy, transport http.RoundTripper) lua.LGFunction {
 	return func(L *lua.LState) int {
 		client := &http.Client{}
-		if proxy.Enable {
+		if transport != nil {
+			client.Transport = transport
+		} else if proxy.Enable {
 			uri, err := url.Parse(proxy.Url)
 			if err == nil {
 				transPort := &http.Transport{
@@ -260,6 +537,9 @@ func createModule(proxy *config.Proxy) lua.LGFunction {
 				}
 			}
 		}
+		if guard := permission.Lookup(L); guard != nil {
+			client.Transport = &guardedTransport{base: client.Transport, guard: guard}
+		}
 		m := &Module{proxy: proxy, client: client}
 		t := L.NewTable()
 		L.SetFuncs(t, m.luaMap())
@@ -268,6 +548,31 @@ func createModule(proxy *config.Proxy) lua.LGFunction {
 	}
 }
 
+// requestContext returns the context of the hook running in L, which aborts the requests of the hook.
+func requestContext(L *lua.LState) context.Context {
+	if ctx := L.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// guardedTransport refuses requests, including redirects, to hosts the plugin did not declare.
+type guardedTransport struct {
+	base  http.RoundTripper
+	guard *permission.Guard
+}
+
+func (t *guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.guard.CheckHost(req.URL.Hostname()); err != nil {
+		return nil, err
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
 func (m *Module) ensureUserAgent(L *lua.LState, req *http.Request) {
 	if req.Header.Get("User-Agent") == "" {
 		navigatorValue := L.GetGlobal(codec.NavigatorObjKey)
@@ -282,5 +587,11 @@ func (m *Module) ensureUserAgent(L *lua.LState, req *http.Request) {
 }
 
 func Preload(L *lua.LState, proxy *config.Proxy) {
-	L.PreloadModule("http", createModule(proxy))
+	PreloadWithTransport(L, proxy, nil)
+}
+
+// PreloadWithTransport preloads the http module sending requests through transport,
+// which takes precedence over the proxy if not nil.
+func PreloadWithTransport(L *lua.LState, proxy *config.Proxy, transport http.RoundTripper) {
+	L.PreloadModule("http", createModule(proxy, transport))
 }
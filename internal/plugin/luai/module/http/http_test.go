@@ -17,11 +17,15 @@
 package http
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -207,3 +211,142 @@ func TestUserAgentAppend(t *testing.T) {
 		t.Fatal("timed out waiting for request")
 	}
 }
+
+func evalServer(t *testing.T, handler http.HandlerFunc, script string) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	backoff := retryBackoff
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = backoff }()
+
+	ls := lua.NewState()
+	defer ls.Close()
+	ls.SetGlobal("serverUrl", lua.LString(server.URL))
+	ls.SetGlobal("tempDir", lua.LString(t.TempDir()))
+	Preload(ls, config.EmptyProxy)
+	if err := ls.DoString(script); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostJSON(t *testing.T) {
+	evalServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Content-Type", r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	}, `
+	local http = require("http")
+	local resp, err = http.post({ url = serverUrl, json = { name = "vfox" } })
+	assert(err == nil, err)
+	assert(resp.status_code == 201)
+	assert(resp.status == "201 Created")
+	assert(resp.headers["X-Method"] == "POST")
+	assert(resp.headers["X-Content-Type"] == "application/json")
+	local data = resp:json()
+	assert(data.name == "vfox")
+
+	resp, err = http.request({ method = "patch", url = serverUrl, body = "text" })
+	assert(err == nil, err)
+	assert(resp.headers["X-Method"] == "PATCH")
+	assert(resp.body == "text")
+
+	resp, err = http.put({ url = serverUrl, body = "not json" })
+	assert(err == nil, err)
+	local value, err = resp:json()
+	assert(value == nil and err ~= nil)
+	`)
+}
+
+func TestRetries(t *testing.T) {
+	var attempts int
+	evalServer(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}, `
+	local http = require("http")
+	local resp, err = http.get({ url = serverUrl, retries = 1 })
+	assert(err == nil, err)
+	assert(resp.status_code == 503)
+	resp, err = http.get({ url = serverUrl, retries = 2 })
+	assert(err == nil, err)
+	assert(resp.status_code == 200)
+	assert(resp.body == "ok")
+	`)
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	evalServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}, `
+	local http = require("http")
+	local resp, err = http.get({ url = serverUrl, timeout = 0.05 })
+	assert(resp == nil)
+	assert(string.find(err, "deadline exceeded"), err)
+	`)
+}
+
+func TestDownloadChecksum(t *testing.T) {
+	const content = "vfox"
+	sum := sha256.Sum256([]byte(content))
+	evalServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(content))
+		}
+	}, fmt.Sprintf(`
+	local http = require("http")
+	local path = tempDir .. "/file"
+	local err = http.download_file({ url = serverUrl, checksum = "sha256:%s" }, path)
+	assert(err == nil, err)
+	err = http.download_file({ url = serverUrl, checksum = "%s" }, path)
+	assert(err == nil, err)
+	err = http.download_file({ url = serverUrl, checksum = "sha256:00" }, path)
+	assert(string.find(err, "checksum mismatch"), err)
+	assert(io.open(path) == nil)
+	err = http.download_file({ url = serverUrl, checksum = "crc:00" }, path)
+	assert(string.find(err, "unsupported checksum algorithm"), err)
+	err = http.download_file({ url = serverUrl .. "/error" }, path)
+	assert(string.find(err, "status code 500"), err)
+	`, hex.EncodeToString(sum[:]), hex.EncodeToString(sum[:])))
+}
+
+func TestDownloadResume(t *testing.T) {
+	const content = "0123456789"
+	sum := sha256.Sum256([]byte(content))
+	var ranges []string
+	evalServer(t, func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "file", time.Time{}, strings.NewReader(content))
+	}, fmt.Sprintf(`
+	local http = require("http")
+	local path = tempDir .. "/file"
+	local f = io.open(path, "w")
+	f:write("01234")
+	f:close()
+	local err = http.download_file({ url = serverUrl, resume = true, checksum = "%s" }, path)
+	assert(err == nil, err)
+	f = io.open(path)
+	assert(f:read("*a") == "0123456789")
+	f:close()
+	err = http.download_file({ url = serverUrl, resume = true, checksum = "%s" }, path)
+	assert(err == nil, err)
+	`, hex.EncodeToString(sum[:]), hex.EncodeToString(sum[:])))
+	if len(ranges) != 2 || ranges[0] != "bytes=5-" || ranges[1] != "bytes=10-" {
+		t.Errorf("unexpected ranges %q", ranges)
+	}
+}
@@ -19,6 +19,9 @@ package json
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"time"
 
 	lua "github.com/yuin/gopher-lua"
 )
@@ -84,75 +87,84 @@ func (i invalidTypeError) Error() string {
 	return `cannot encode ` + lua.LValueType(i).String() + ` to JSON`
 }
 
-// Encode returns the JSON encoding of value.
+// Encode returns the JSON encoding of value, converted with ToGo.
 func Encode(value lua.LValue) ([]byte, error) {
-	return json.Marshal(jsonValue{
-		LValue:  value,
-		visited: make(map[*lua.LTable]bool),
-	})
+	converted, err := ToGo(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(converted)
 }
 
-type jsonValue struct {
-	lua.LValue
-	visited map[*lua.LTable]bool
+// ToGo converts value to the Go values encoding/json decodes to, with the rules of Encode:
+// tables with string keys become maps, sequences and empty tables become slices, and
+// integral numbers become int64, so that encoders do not write them as floats.
+func ToGo(value lua.LValue) (any, error) {
+	return toGo(value, make(map[*lua.LTable]bool))
 }
 
-func (j jsonValue) MarshalJSON() (data []byte, err error) {
-	switch converted := j.LValue.(type) {
+func toGo(value lua.LValue, visited map[*lua.LTable]bool) (any, error) {
+	switch converted := value.(type) {
 	case lua.LBool:
-		data, err = json.Marshal(bool(converted))
+		return bool(converted), nil
 	case lua.LNumber:
-		data, err = json.Marshal(float64(converted))
+		if f := float64(converted); f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+			return int64(f), nil
+		}
+		return float64(converted), nil
 	case *lua.LNilType:
-		data = []byte(`null`)
+		return nil, nil
 	case lua.LString:
-		data, err = json.Marshal(string(converted))
+		return string(converted), nil
 	case *lua.LTable:
-		if j.visited[converted] {
+		if visited[converted] {
 			return nil, errNested
 		}
-		j.visited[converted] = true
-
-		key, value := converted.Next(lua.LNil)
+		visited[converted] = true
+		defer delete(visited, converted)
 
+		key, item := converted.Next(lua.LNil)
 		switch key.Type() {
 		case lua.LTNil: // empty table
-			data = []byte(`[]`)
+			return []any{}, nil
 		case lua.LTNumber:
-			arr := make([]jsonValue, 0, converted.Len())
+			arr := make([]any, 0, converted.Len())
 			expectedKey := lua.LNumber(1)
 			for key != lua.LNil {
 				if key.Type() != lua.LTNumber {
-					err = errInvalidKeys
-					return
+					return nil, errInvalidKeys
 				}
 				if expectedKey != key {
-					err = errSparseArray
-					return
+					return nil, errSparseArray
 				}
-				arr = append(arr, jsonValue{value, j.visited})
+				v, err := toGo(item, visited)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
 				expectedKey++
-				key, value = converted.Next(key)
+				key, item = converted.Next(key)
 			}
-			data, err = json.Marshal(arr)
+			return arr, nil
 		case lua.LTString:
-			obj := make(map[string]jsonValue)
+			obj := make(map[string]any)
 			for key != lua.LNil {
 				if key.Type() != lua.LTString {
-					err = errInvalidKeys
-					return
+					return nil, errInvalidKeys
+				}
+				v, err := toGo(item, visited)
+				if err != nil {
+					return nil, err
 				}
-				obj[key.String()] = jsonValue{value, j.visited}
-				key, value = converted.Next(key)
+				obj[key.String()] = v
+				key, item = converted.Next(key)
 			}
-			data, err = json.Marshal(obj)
+			return obj, nil
 		default:
-			err = errInvalidKeys
+			return nil, errInvalidKeys
 		}
-	default:
-		err = invalidTypeError(j.LValue.Type())
 	}
-	return
+	return nil, invalidTypeError(value.Type())
 }
 
 // Decode converts the JSON encoded data to Lua values.
@@ -167,7 +179,8 @@ func Decode(L *lua.LState, data []byte) (lua.LValue, error) {
 
 // DecodeValue converts the value to a Lua value.
 //
-// This function only converts values that the encoding/json package decodes to.
+// This function converts the values that the encoding/json, toml and yaml packages decode to:
+// numbers become Lua numbers, and times and other fmt.Stringer values become strings.
 // All other values will return lua.LNil.
 func DecodeValue(L *lua.LState, value interface{}) lua.LValue {
 	switch converted := value.(type) {
@@ -179,6 +192,14 @@ func DecodeValue(L *lua.LState, value interface{}) lua.LValue {
 		return lua.LString(converted)
 	case json.Number:
 		return lua.LString(converted)
+	case int:
+		return lua.LNumber(converted)
+	case int64:
+		return lua.LNumber(converted)
+	case uint64:
+		return lua.LNumber(converted)
+	case time.Time:
+		return lua.LString(converted.Format(time.RFC3339Nano))
 	case []interface{}:
 		arr := L.CreateTable(len(converted), 0)
 		for _, item := range converted {
@@ -191,6 +212,22 @@ func DecodeValue(L *lua.LState, value interface{}) lua.LValue {
 			tbl.RawSetH(lua.LString(key), DecodeValue(L, item))
 		}
 		return tbl
+	case []map[string]interface{}:
+		arr := L.CreateTable(len(converted), 0)
+		for _, item := range converted {
+			arr.Append(DecodeValue(L, item))
+		}
+		return arr
+	case map[interface{}]interface{}:
+		tbl := L.CreateTable(0, len(converted))
+		for key, item := range converted {
+			if k := DecodeValue(L, key); k != lua.LNil {
+				tbl.RawSet(k, DecodeValue(L, item))
+			}
+		}
+		return tbl
+	case fmt.Stringer:
+		return lua.LString(converted.String())
 	case nil:
 		return lua.LNil
 	}
@@ -106,6 +106,10 @@ func TestSimple(t *testing.T) {
 	obj.obj2 = obj2
 	assert(json.encode(obj) == nil, "json.encode(obj) is not nil")
 
+	local shared = {1}
+	assert(json.encode({a = shared, b = shared}) == '{"a":[1],"b":[1]}', "a table referenced twice is not nested")
+	assert(json.encode(1.5) == "1.5", "json.encode(1.5) is not '1.5'")
+
 	local a = {}
 	for i=1, 5 do
 		a[i] = i
@@ -0,0 +1,73 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/version-fox/vfox/internal/shared/logger"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Preload adds log to the given Lua state's package.preload table, prefixing the
+// messages with name. After it has been preloaded, it can be loaded using require:
+//
+//	local log = require("log")
+func Preload(L *lua.LState, name string) {
+	L.PreloadModule("log", func(L *lua.LState) int {
+		t := L.NewTable()
+		L.SetFuncs(t, map[string]lua.LGFunction{
+			"debug": logFunc(name, logger.DebugLevel),
+			"info":  logFunc(name, logger.InfoLevel),
+			"warn":  logFunc(name, logger.WarnLevel),
+			"error": logFunc(name, logger.ErrorLevel),
+		})
+		L.Push(t)
+		return 1
+	})
+}
+
+// logFunc returns a function logging its arguments at level, converted as by print.
+func logFunc(name string, level logger.LoggerLevel) lua.LGFunction {
+	return func(L *lua.LState) int {
+		message := message(L)
+		if name != "" {
+			message = "[" + name + "] " + message
+		}
+		logger.Log(level, message)
+		return 0
+	}
+}
+
+// RedirectPrint makes print write to w instead of stdout.
+func RedirectPrint(L *lua.LState, w io.Writer) {
+	L.SetGlobal("print", L.NewFunction(func(L *lua.LState) int {
+		_, _ = fmt.Fprintln(w, message(L))
+		return 0
+	}))
+}
+
+// message joins the arguments of the call with tabs, as print does.
+func message(L *lua.LState) string {
+	parts := make([]string, L.GetTop())
+	for i := range parts {
+		parts[i] = L.ToStringMeta(L.Get(i + 1)).String()
+	}
+	return strings.Join(parts, "\t")
+}
@@ -0,0 +1,72 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/version-fox/vfox/internal/shared/logger"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	defer logger.SetOutput(os.Stderr)
+
+	s := lua.NewState()
+	defer s.Close()
+	Preload(s, "nodejs")
+	if err := s.DoString(`
+	local log = require("log")
+	log.debug("hidden")
+	log.info("installing", 20, true)
+	log.warn("deprecated")
+	log.error({} == nil)
+	`); err != nil {
+		t.Fatal(err)
+	}
+	expected := "[nodejs] installing\t20\ttrue\n[nodejs] deprecated\n[nodejs] false\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	logger.SetLevel(logger.DebugLevel)
+	defer logger.SetLevel(logger.InfoLevel)
+	if err := s.DoString(`require("log").debug("shown")`); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[nodejs] shown\n" {
+		t.Errorf("expected debug message, got %q", buf.String())
+	}
+}
+
+func TestRedirectPrint(t *testing.T) {
+	var buf bytes.Buffer
+	s := lua.NewState()
+	defer s.Close()
+	RedirectPrint(s, &buf)
+	if err := s.DoString(`print("a", 1, nil)`); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a\t1\tnil\n" {
+		t.Errorf("expected print to be redirected, got %q", buf.String())
+	}
+}
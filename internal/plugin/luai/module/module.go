@@ -17,23 +17,52 @@
 package module
 
 import (
+	"io"
+	gohttp "net/http"
+
 	"github.com/version-fox/vfox/internal/config"
 	"github.com/version-fox/vfox/internal/plugin/luai/module/archiver"
+	"github.com/version-fox/vfox/internal/plugin/luai/module/cmd"
+	"github.com/version-fox/vfox/internal/plugin/luai/module/file"
 	"github.com/version-fox/vfox/internal/plugin/luai/module/html"
 	"github.com/version-fox/vfox/internal/plugin/luai/module/http"
 	"github.com/version-fox/vfox/internal/plugin/luai/module/json"
-	"github.com/version-fox/vfox/internal/plugin/luai/module/string"
+	"github.com/version-fox/vfox/internal/plugin/luai/module/log"
+	"github.com/version-fox/vfox/internal/plugin/luai/module/semver"
+	strings "github.com/version-fox/vfox/internal/plugin/luai/module/string"
+	"github.com/version-fox/vfox/internal/plugin/luai/module/toml"
+	"github.com/version-fox/vfox/internal/plugin/luai/module/xml"
+	"github.com/version-fox/vfox/internal/plugin/luai/module/yaml"
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
 	lua "github.com/yuin/gopher-lua"
 )
 
 type PreloadOptions struct {
 	Config *config.Config
+	// Transport, if set, replaces the transport of the http module.
+	Transport gohttp.RoundTripper
+	// Guard, if set, confines the plugin to the permissions it declares.
+	Guard *permission.Guard
+	// Name prefixes the messages of the log module.
+	Name string
+	// Stdout, if set, is where print writes instead of stdout.
+	Stdout io.Writer
 }
 
 func Preload(L *lua.LState, options *PreloadOptions) {
-	http.Preload(L, options.Config.Proxy)
+	http.PreloadWithTransport(L, options.Config.Proxy, options.Transport)
 	json.Preload(L)
+	yaml.Preload(L)
+	toml.Preload(L)
+	xml.Preload(L)
 	html.Preload(L)
-	string.Preload(L)
+	strings.Preload(L)
 	archiver.Preload(L)
+	semver.Preload(L)
+	cmd.Preload(L)
+	file.Preload(L)
+	log.Preload(L, options.Name)
+	if options.Stdout != nil {
+		log.RedirectPrint(L, options.Stdout)
+	}
 }
@@ -0,0 +1,193 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package semver
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/version-fox/vfox/internal/shared/semver"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Preload adds semver to the given Lua state's package.preload table. After it
+// has been preloaded, it can be loaded using require:
+//
+//	local semver = require("semver")
+func Preload(L *lua.LState) {
+	L.PreloadModule("semver", loader)
+}
+
+// loader is the module loader function.
+func loader(L *lua.LState) int {
+	t := L.NewTable()
+	L.SetFuncs(t, api)
+	L.Push(t)
+	return 1
+}
+
+var api = map[string]lua.LGFunction{
+	"parse":          parse,
+	"compare":        compare,
+	"sort":           sortVersions,
+	"satisfies":      satisfies,
+	"max_satisfying": maxSatisfying,
+	"is_prerelease":  isPrerelease,
+}
+
+// parse lua semver.parse(version) returns a table of major, minor, patch, numbers,
+// prerelease, build and version, the canonical form, or nil and an error.
+func parse(L *lua.LState) int {
+	v, err := semver.Parse(L.CheckString(1))
+	if err != nil {
+		return pushError(L, err)
+	}
+	t := L.NewTable()
+	t.RawSetString("major", lua.LNumber(v.Number(0)))
+	t.RawSetString("minor", lua.LNumber(v.Number(1)))
+	t.RawSetString("patch", lua.LNumber(v.Number(2)))
+	numbers := L.CreateTable(len(v.Numbers), 0)
+	for _, n := range v.Numbers {
+		numbers.Append(lua.LNumber(n))
+	}
+	t.RawSetString("numbers", numbers)
+	t.RawSetString("prerelease", lua.LString(strings.Join(v.Prerelease, ".")))
+	t.RawSetString("build", lua.LString(v.Build))
+	t.RawSetString("version", lua.LString(v.String()))
+	L.Push(t)
+	return 1
+}
+
+// compare lua semver.compare(a, b) returns -1, 0 or 1 if a is older than, the same as,
+// or newer than b, or nil and an error.
+func compare(L *lua.LState) int {
+	a, err := semver.Parse(L.CheckString(1))
+	if err != nil {
+		return pushError(L, err)
+	}
+	b, err := semver.Parse(L.CheckString(2))
+	if err != nil {
+		return pushError(L, err)
+	}
+	L.Push(lua.LNumber(a.Compare(b)))
+	return 1
+}
+
+// sortVersions lua semver.sort(versions, order) returns a new table of versions sorted in
+// ascending order, or descending if order is "desc". Versions may be strings, or tables
+// with a version field such as the results of the Available hook. Invalid versions come last.
+func sortVersions(L *lua.LState) int {
+	items := parseItems(L.CheckTable(1))
+	desc := L.OptString(2, "asc") == "desc"
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].version, items[j].version
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if desc {
+			return a.Compare(b) > 0
+		}
+		return a.Compare(b) < 0
+	})
+	result := L.CreateTable(len(items), 0)
+	for _, item := range items {
+		result.Append(item.value)
+	}
+	L.Push(result)
+	return 1
+}
+
+// satisfies lua semver.satisfies(version, range, includePrerelease) returns whether version is in
+// range, written as in npm such as "^1.2 || >=2.1.0-rc.1", or nil and an error.
+func satisfies(L *lua.LState) int {
+	v, err := semver.Parse(L.CheckString(1))
+	if err != nil {
+		return pushError(L, err)
+	}
+	r, err := semver.ParseRange(L.CheckString(2))
+	if err != nil {
+		return pushError(L, err)
+	}
+	L.Push(lua.LBool(r.Contains(v, L.OptBool(3, false))))
+	return 1
+}
+
+// maxSatisfying lua semver.max_satisfying(versions, range, includePrerelease) returns the newest
+// of versions in range, nil if none is, or nil and an error.
+func maxSatisfying(L *lua.LState) int {
+	items := parseItems(L.CheckTable(1))
+	r, err := semver.ParseRange(L.CheckString(2))
+	if err != nil {
+		return pushError(L, err)
+	}
+	includePrerelease := L.OptBool(3, false)
+	var best *item
+	for i := range items {
+		v := items[i].version
+		if v == nil || !r.Contains(v, includePrerelease) {
+			continue
+		}
+		if best == nil || v.Compare(best.version) > 0 {
+			best = &items[i]
+		}
+	}
+	if best == nil {
+		L.Push(lua.LNil)
+		return 1
+	}
+	L.Push(best.value)
+	return 1
+}
+
+// isPrerelease lua semver.is_prerelease(version) returns whether version is a pre-release,
+// or nil and an error.
+func isPrerelease(L *lua.LState) int {
+	v, err := semver.Parse(L.CheckString(1))
+	if err != nil {
+		return pushError(L, err)
+	}
+	L.Push(lua.LBool(v.IsPrerelease()))
+	return 1
+}
+
+type item struct {
+	value   lua.LValue
+	version *semver.Version
+}
+
+func parseItems(t *lua.LTable) []item {
+	items := make([]item, 0, t.Len())
+	for i := 1; i <= t.Len(); i++ {
+		value := t.RawGetInt(i)
+		s := value
+		if tv, ok := value.(*lua.LTable); ok {
+			s = tv.RawGetString("version")
+		}
+		it := item{value: value}
+		if str, ok := s.(lua.LString); ok {
+			it.version, _ = semver.Parse(string(str))
+		}
+		items = append(items, it)
+	}
+	return items
+}
+
+func pushError(L *lua.LState, err error) int {
+	L.Push(lua.LNil)
+	L.Push(lua.LString(err.Error()))
+	return 2
+}
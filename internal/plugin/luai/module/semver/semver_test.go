@@ -0,0 +1,59 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package semver
+
+import (
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestLua(t *testing.T) {
+	const str = `
+	local semver = require("semver")
+
+	local v = semver.parse("go1.22rc1")
+	assert(v.major == 1 and v.minor == 22 and v.patch == 0, "semver.parse() numbers")
+	assert(v.prerelease == "rc.1", "semver.parse() prerelease: " .. v.prerelease)
+	assert(v.version == "1.22.0-rc.1", "semver.parse() version: " .. v.version)
+	assert(semver.parse("jdk-21+35").build == "35", "semver.parse() build")
+	local _, err = semver.parse("latest")
+	assert(err ~= nil, "semver.parse() error")
+
+	assert(semver.compare("1.0.0-rc.1", "1.0.0") == -1, "semver.compare()")
+	assert(semver.compare("1.10.0", "1.9.0") == 1, "semver.compare()")
+	assert(semver.is_prerelease("1.0.0-beta"), "semver.is_prerelease()")
+
+	local sorted = semver.sort({ "1.10.0", "latest", "1.9.0", "1.10.0-rc.1" })
+	assert(table.concat(sorted, ",") == "1.9.0,1.10.0-rc.1,1.10.0,latest", "semver.sort(): " .. table.concat(sorted, ","))
+	local desc = semver.sort({ { version = "1.2.0" }, { version = "1.10.0" } }, "desc")
+	assert(desc[1].version == "1.10.0", "semver.sort() desc")
+
+	assert(semver.satisfies("1.9.0", "^1.2"), "semver.satisfies()")
+	assert(not semver.satisfies("2.1.0-rc.1", "^1.2 || ^2"), "semver.satisfies() pre-release")
+	assert(semver.satisfies("2.1.0-rc.1", "^1.2 || ^2", true), "semver.satisfies() include pre-release")
+	assert(semver.max_satisfying({ "18.1.0", "20.3.1", "20.11.0", "21.0.0" }, "20") == "20.11.0", "semver.max_satisfying()")
+	assert(semver.max_satisfying({ "18.1.0" }, "20") == nil, "semver.max_satisfying() none")
+	`
+	s := lua.NewState()
+	defer s.Close()
+
+	Preload(s)
+	if err := s.DoString(str); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package toml
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/version-fox/vfox/internal/plugin/luai/module/json"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Preload adds toml to the given Lua state's package.preload table. After it
+// has been preloaded, it can be loaded using require:
+//
+//	local toml = require("toml")
+func Preload(L *lua.LState) {
+	L.PreloadModule("toml", loader)
+}
+
+// loader is the module loader function.
+func loader(L *lua.LState) int {
+	t := L.NewTable()
+	L.SetFuncs(t, api)
+	L.Push(t)
+	return 1
+}
+
+var api = map[string]lua.LGFunction{
+	"decode": apiDecode,
+	"encode": apiEncode,
+}
+
+func apiDecode(L *lua.LState) int {
+	str := L.CheckString(1)
+
+	value, err := Decode(L, []byte(str))
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+	L.Push(value)
+	return 1
+}
+
+func apiEncode(L *lua.LState) int {
+	value := L.CheckAny(1)
+
+	data, err := Encode(value)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+	L.Push(lua.LString(string(data)))
+	return 1
+}
+
+var errNotTable = errors.New("cannot encode a value other than a table with string keys to TOML")
+
+// Encode returns the TOML encoding of value, a table with string keys, with the rules of json.Encode.
+func Encode(value lua.LValue) ([]byte, error) {
+	v, err := json.ToGo(value)
+	if err != nil {
+		return nil, err
+	}
+	switch converted := v.(type) {
+	case map[string]any:
+		var buf bytes.Buffer
+		if err = toml.NewEncoder(&buf).Encode(converted); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case []any:
+		if len(converted) == 0 { // empty table
+			return []byte{}, nil
+		}
+	}
+	return nil, errNotTable
+}
+
+// Decode converts the TOML encoded data to Lua values. Dates and times become strings.
+func Decode(L *lua.LState, data []byte) (lua.LValue, error) {
+	var value map[string]interface{}
+	_, err := toml.Decode(string(data), &value)
+	if err != nil {
+		return nil, err
+	}
+	return json.DecodeValue(L, formatTimes(value)), nil
+}
+
+// formatTimes replaces the times in value by strings in TOML format, keeping local dates
+// and times, which the decoder returns as times in zones of these names, without a zone.
+func formatTimes(value any) any {
+	switch converted := value.(type) {
+	case time.Time:
+		switch converted.Location().String() {
+		case "date-local":
+			return converted.Format(time.DateOnly)
+		case "time-local":
+			return converted.Format("15:04:05.999999999")
+		case "datetime-local":
+			return converted.Format("2006-01-02T15:04:05.999999999")
+		}
+		return converted.Format(time.RFC3339Nano)
+	case []any:
+		for i, item := range converted {
+			converted[i] = formatTimes(item)
+		}
+	case []map[string]any:
+		for _, item := range converted {
+			formatTimes(item)
+		}
+	case map[string]any:
+		for key, item := range converted {
+			converted[key] = formatTimes(item)
+		}
+	}
+	return value
+}
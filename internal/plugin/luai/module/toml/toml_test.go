@@ -0,0 +1,66 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package toml
+
+import (
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestToml(t *testing.T) {
+	const str = `
+	local toml = require("toml")
+	assert(type(toml.decode) == "function", "toml.decode is not a function")
+	assert(type(toml.encode) == "function", "toml.encode is not a function")
+
+	local doc = toml.decode([==[
+[toolchain]
+channel = "1.76.0"
+components = ["rustfmt", "clippy"]
+profile = "minimal"
+released = 2024-02-08
+updated = 2024-02-08T10:00:00Z
+
+[[targets]]
+name = "wasm32"
+tier = 2
+]==])
+	assert(doc.toolchain.channel == "1.76.0")
+	assert(doc.toolchain.components[2] == "clippy")
+	assert(doc.toolchain.released == "2024-02-08")
+	assert(doc.toolchain.updated == "2024-02-08T10:00:00Z")
+	assert(doc.targets[1].name == "wasm32")
+	assert(doc.targets[1].tier == 2)
+
+	assert(toml.encode({ version = 1, ratio = 0.5 }) == "ratio = 0.5\nversion = 1\n")
+	assert(toml.encode({}) == "")
+	local doc = toml.decode(toml.encode({ toolchain = { channel = "stable" } }))
+	assert(doc.toolchain.channel == "stable")
+
+	local _, err = toml.decode("a = ")
+	assert(err ~= nil)
+	local _, err = toml.encode({ 1, 2 })
+	assert(string.find(err, "table with string keys"), err)
+	`
+	s := lua.NewState()
+	defer s.Close()
+	Preload(s)
+	if err := s.DoString(str); err != nil {
+		t.Error(err)
+	}
+}
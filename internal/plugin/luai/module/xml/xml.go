@@ -0,0 +1,191 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Preload adds xml to the given Lua state's package.preload table. After it
+// has been preloaded, it can be loaded using require:
+//
+//	local xml = require("xml")
+//
+// Elements are tables of their name, attributes, text and child elements:
+//
+//	{ name = "version", attrs = { id = "1" }, text = "3.9.6", children = {} }
+func Preload(L *lua.LState) {
+	L.PreloadModule("xml", loader)
+}
+
+// loader is the module loader function.
+func loader(L *lua.LState) int {
+	t := L.NewTable()
+	L.SetFuncs(t, api)
+	L.Push(t)
+	return 1
+}
+
+var api = map[string]lua.LGFunction{
+	"decode": apiDecode,
+	"encode": apiEncode,
+}
+
+func apiDecode(L *lua.LState) int {
+	str := L.CheckString(1)
+
+	value, err := Decode(L, []byte(str))
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+	L.Push(value)
+	return 1
+}
+
+func apiEncode(L *lua.LState) int {
+	value := L.CheckTable(1)
+
+	data, err := Encode(value)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+	L.Push(lua.LString(string(data)))
+	return 1
+}
+
+var (
+	errNoElement   = errors.New("no root element in XML")
+	errNested      = errors.New("cannot encode recursively nested tables to XML")
+	errMissingName = errors.New("cannot encode an element without name to XML")
+)
+
+// Decode converts the root element of the XML encoded data to a Lua table. The text of an
+// element is its character data with surrounding whitespace trimmed.
+func Decode(L *lua.LState, data []byte) (lua.LValue, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*lua.LTable
+	var texts []*strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errNoElement
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := L.NewTable()
+			element.RawSetString("name", lua.LString(t.Name.Local))
+			attrs := L.NewTable()
+			for _, attr := range t.Attr {
+				attrs.RawSetString(attrName(attr.Name), lua.LString(attr.Value))
+			}
+			element.RawSetString("attrs", attrs)
+			element.RawSetString("children", L.NewTable())
+			if len(stack) > 0 {
+				stack[len(stack)-1].RawGetString("children").(*lua.LTable).Append(element)
+			}
+			stack = append(stack, element)
+			texts = append(texts, &strings.Builder{})
+		case xml.CharData:
+			if len(texts) > 0 {
+				texts[len(texts)-1].Write(t)
+			}
+		case xml.EndElement:
+			element := stack[len(stack)-1]
+			element.RawSetString("text", lua.LString(strings.TrimSpace(texts[len(texts)-1].String())))
+			stack, texts = stack[:len(stack)-1], texts[:len(texts)-1]
+			if len(stack) == 0 {
+				return element, nil
+			}
+		}
+	}
+}
+
+// attrName keeps the prefix of namespace declarations, which the decoder resolves for other names.
+func attrName(name xml.Name) string {
+	if name.Space == "xmlns" {
+		return "xmlns:" + name.Local
+	}
+	return name.Local
+}
+
+// Encode returns the XML encoding of element, a table as returned by Decode. Attributes
+// are written in sorted order, and text before the child elements.
+func Encode(element *lua.LTable) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	if err := encode(encoder, element, make(map[*lua.LTable]bool)); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encode(encoder *xml.Encoder, element *lua.LTable, visited map[*lua.LTable]bool) error {
+	if visited[element] {
+		return errNested
+	}
+	visited[element] = true
+	defer delete(visited, element)
+
+	name, ok := element.RawGetString("name").(lua.LString)
+	if !ok || name == "" {
+		return errMissingName
+	}
+	start := xml.StartElement{Name: xml.Name{Local: string(name)}}
+	if attrs, ok := element.RawGetString("attrs").(*lua.LTable); ok {
+		attrs.ForEach(func(key, value lua.LValue) {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: key.String()}, Value: value.String()})
+		})
+		sort.Slice(start.Attr, func(i, j int) bool { return start.Attr[i].Name.Local < start.Attr[j].Name.Local })
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	if text := element.RawGetString("text"); text != lua.LNil {
+		if err := encoder.EncodeToken(xml.CharData(text.String())); err != nil {
+			return err
+		}
+	}
+	if children, ok := element.RawGetString("children").(*lua.LTable); ok {
+		for i := 1; i <= children.Len(); i++ {
+			child, ok := children.RawGetInt(i).(*lua.LTable)
+			if !ok {
+				return errMissingName
+			}
+			if err := encode(encoder, child, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
@@ -0,0 +1,75 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xml
+
+import (
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestXml(t *testing.T) {
+	const str = `
+	local xml = require("xml")
+	assert(type(xml.decode) == "function", "xml.decode is not a function")
+	assert(type(xml.encode) == "function", "xml.encode is not a function")
+
+	local doc = xml.decode([[
+<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>org.apache.maven</groupId>
+  <versioning>
+    <latest>3.9.6</latest>
+    <versions>
+      <version>3.9.5</version>
+      <version>3.9.6</version>
+    </versions>
+  </versioning>
+</metadata>
+]])
+	assert(doc.name == "metadata")
+	assert(doc.attrs.modelVersion == "1.1.0")
+	assert(doc.children[1].name == "groupId")
+	assert(doc.children[1].text == "org.apache.maven")
+	local versions = doc.children[2].children[2].children
+	assert(#versions == 2)
+	assert(versions[2].text == "3.9.6")
+
+	local encoded = xml.encode({
+		name = "version",
+		attrs = { id = 1, kind = "a&b" },
+		text = "1.0",
+		children = { { name = "child" } },
+	})
+	assert(encoded == '<version id="1" kind="a&amp;b">1.0<child></child></version>', encoded)
+	local doc = xml.decode(encoded)
+	assert(doc.attrs.kind == "a&b" and doc.children[1].name == "child")
+
+	local _, err = xml.decode("")
+	assert(err == "no root element in XML", err)
+	local _, err = xml.decode("<a>")
+	assert(err ~= nil)
+	local _, err = xml.encode({ text = "x" })
+	assert(string.find(err, "without name"), err)
+	`
+	s := lua.NewState()
+	defer s.Close()
+	Preload(s)
+	if err := s.DoString(str); err != nil {
+		t.Error(err)
+	}
+}
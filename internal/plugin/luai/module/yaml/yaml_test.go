@@ -0,0 +1,63 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package yaml
+
+import (
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestYaml(t *testing.T) {
+	const str = `
+	local yaml = require("yaml")
+	assert(type(yaml.decode) == "function", "yaml.decode is not a function")
+	assert(type(yaml.encode) == "function", "yaml.encode is not a function")
+
+	local doc = yaml.decode([[
+name: app
+environment:
+  sdk: ">=3.0.0 <4.0.0"
+  flutter: 3.19.0
+version: 1.2
+build: 12
+tags: [a, b]
+]])
+	assert(doc.name == "app")
+	assert(doc.environment.sdk == ">=3.0.0 <4.0.0")
+	assert(doc.environment.flutter == "3.19.0")
+	assert(doc.version == 1.2)
+	assert(doc.build == 12)
+	assert(doc.tags[2] == "b")
+
+	assert(yaml.encode({ build = 12, version = 1.5 }) == "build: 12\nversion: 1.5\n")
+	assert(yaml.encode({ "a", 1 }) == "- a\n- 1\n")
+	local doc = yaml.decode(yaml.encode({ name = "app", list = { 1, 2 } }))
+	assert(doc.name == "app" and doc.list[2] == 2)
+
+	local _, err = yaml.decode("a: [")
+	assert(err ~= nil)
+	local _, err = yaml.encode({1, 2, [10] = 3})
+	assert(string.find(err, "sparse array"), err)
+	`
+	s := lua.NewState()
+	defer s.Close()
+	Preload(s)
+	if err := s.DoString(str); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,355 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package permission confines what a Lua plugin may do to the permissions it declares in
+// its metadata: the hosts it may reach, the paths it may read and write and whether it may run processes.
+package permission
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+var ErrDenied = errors.New("permission denied")
+
+// All is declared as PLUGIN.permissions to opt in to unrestricted access.
+const All = "*"
+
+// Permissions are declared by a plugin in PLUGIN.permissions:
+//
+//	PLUGIN.permissions = {
+//	    network = { "nodejs.org", "*.github.com" },
+//	    readable = { "/etc/os-release" },
+//	    writable = { "~/.npmrc" },
+//	    exec = true,
+//	}
+//
+// A plugin needing unrestricted access declares PLUGIN.permissions = "*". A plugin declaring
+// no permissions at all, as every plugin written before they existed, is unrestricted too;
+// this is deprecated.
+type Permissions struct {
+	// Network lists the hosts the plugin may reach. "*.example.com" matches the subdomains
+	// of example.com, and "*" matches every host.
+	Network []string `json:"network"`
+	// Readable lists paths the plugin may read, in addition to the writable ones, its own
+	// directory, its install directory and the temporary directories.
+	Readable []string `json:"readable"`
+	// Writable lists paths the plugin may write, in addition to its install directory and
+	// the temporary directories. "~" is the home directory.
+	Writable []string `json:"writable"`
+	// Exec allows running processes.
+	Exec bool `json:"exec"`
+	// Unrestricted is set by declaring All, granting everything.
+	Unrestricted bool `json:"-"`
+}
+
+// Describe returns a line for every permission of p, for approval prompts.
+func Describe(p *Permissions) []string {
+	if p == nil {
+		return []string{"unrestricted access: no PLUGIN.permissions declared (deprecated)"}
+	}
+	if p.Unrestricted {
+		return []string{"unrestricted access: every host, path and process"}
+	}
+	var lines []string
+	for _, host := range p.Network {
+		lines = append(lines, "network: "+host)
+	}
+	for _, path := range p.Readable {
+		lines = append(lines, "read: "+path)
+	}
+	for _, path := range p.Writable {
+		lines = append(lines, "write: "+path)
+	}
+	if p.Exec {
+		lines = append(lines, "exec: run processes")
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "none besides writing its install directory")
+	}
+	return lines
+}
+
+// Widened returns the permissions of next which previous did not grant, described as by Describe.
+func Widened(previous, next *Permissions) []string {
+	if previous == nil || previous.Unrestricted {
+		return nil
+	}
+	if next == nil || next.Unrestricted {
+		return Describe(next)
+	}
+	var lines []string
+	for _, host := range next.Network {
+		if !matchAny(previous.Network, host) {
+			lines = append(lines, "network: "+host)
+		}
+	}
+	for _, path := range next.Readable {
+		if !contains(previous.Readable, path) && !contains(previous.Writable, path) {
+			lines = append(lines, "read: "+path)
+		}
+	}
+	for _, path := range next.Writable {
+		if !contains(previous.Writable, path) {
+			lines = append(lines, "write: "+path)
+		}
+	}
+	if next.Exec && !previous.Exec {
+		lines = append(lines, "exec: run processes")
+	}
+	return lines
+}
+
+// Guard enforces permissions. Until Grant is called, only the default paths are readable and
+// writable, so the top-level code of plugin scripts runs with no permissions. A nil Guard
+// allows everything.
+type Guard struct {
+	mu           sync.RWMutex
+	permissions  *Permissions
+	defaults     []string
+	writable     []string
+	readable     []string
+	unrestricted bool
+}
+
+// NewGuard creates a guard allowing reads and writes under the given default paths.
+func NewGuard(defaultWritable ...string) *Guard {
+	g := &Guard{}
+	for _, p := range defaultWritable {
+		if p != "" {
+			g.defaults = append(g.defaults, resolve(p))
+		}
+	}
+	g.writable = g.defaults
+	g.permissions = &Permissions{}
+	return g
+}
+
+// Grant applies the permissions declared by the plugin. nil, for a plugin declaring none,
+// grants everything like All.
+func (g *Guard) Grant(p *Permissions) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if p == nil {
+		p = &Permissions{Unrestricted: true}
+	}
+	g.permissions = p
+	g.unrestricted = p.Unrestricted
+	g.writable = append([]string(nil), g.defaults...)
+	g.readable = nil
+	for _, path := range p.Writable {
+		g.writable = append(g.writable, resolve(expandHome(path)))
+	}
+	for _, path := range p.Readable {
+		g.readable = append(g.readable, resolve(expandHome(path)))
+	}
+}
+
+// AllowRead allows reading the given paths, such as files the user asks the plugin to parse.
+func (g *Guard) AllowRead(paths ...string) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, path := range paths {
+		g.readable = append(g.readable, resolve(path))
+	}
+}
+
+// Unrestricted reports whether the plugin declared All.
+func (g *Guard) Unrestricted() bool {
+	if g == nil {
+		return true
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.unrestricted
+}
+
+// CheckHost returns an error unless the plugin may reach host.
+func (g *Guard) CheckHost(host string) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.unrestricted || matchAny(g.permissions.Network, host) {
+		return nil
+	}
+	return fmt.Errorf("%w: network access to %s is not declared in PLUGIN.permissions.network", ErrDenied, host)
+}
+
+// CheckWrite returns an error unless the plugin may write path.
+func (g *Guard) CheckWrite(path string) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.unrestricted {
+		return nil
+	}
+	if g.isWritable(resolve(path)) {
+		return nil
+	}
+	return fmt.Errorf("%w: writing %s is not declared in PLUGIN.permissions.writable", ErrDenied, path)
+}
+
+// CheckRead returns an error unless the plugin may read path.
+func (g *Guard) CheckRead(path string) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.unrestricted {
+		return nil
+	}
+	resolved := resolve(path)
+	if g.isWritable(resolved) {
+		return nil
+	}
+	for _, root := range g.readable {
+		if isWithin(root, resolved) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: reading %s is not declared in PLUGIN.permissions.readable", ErrDenied, path)
+}
+
+// CheckConfined returns an error unless path is within the writable paths, even if the
+// plugin declared All.
+func (g *Guard) CheckConfined(path string) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.isWritable(resolve(path)) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is outside the directories the plugin may write", ErrDenied, path)
+}
+
+// CheckExec returns an error unless the plugin may run command.
+func (g *Guard) CheckExec(command string) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.unrestricted || g.permissions.Exec {
+		return nil
+	}
+	return fmt.Errorf("%w: running %q requires PLUGIN.permissions.exec", ErrDenied, command)
+}
+
+func (g *Guard) isWritable(resolved string) bool {
+	for _, root := range g.writable {
+		if isWithin(root, resolved) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandHome replaces a leading "~" of path with the home directory.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
+
+func matchAny(patterns []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*" || pattern == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok && strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve returns the absolute path of path with the symlinks of its existing
+// ancestors evaluated, so that a link cannot lead out of a writable directory.
+func resolve(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	rest := ""
+	dir := abs
+	for {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(real, rest)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// guards holds the guard of every Lua state, keyed by its global state so that
+// coroutines share the guard of the state that created them.
+var guards sync.Map
+
+// Attach makes g the guard of L, see Lookup.
+func Attach(L *lua.LState, g *Guard) {
+	guards.Store(L.G, g)
+}
+
+// Detach removes the guard of L, when it is closed.
+func Detach(L *lua.LState) {
+	guards.Delete(L.G)
+}
+
+// Lookup returns the guard of L, or nil if L has none.
+func Lookup(L *lua.LState) *Guard {
+	if g, ok := guards.Load(L.G); ok {
+		return g.(*Guard)
+	}
+	return nil
+}
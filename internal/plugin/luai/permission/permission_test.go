@@ -0,0 +1,186 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package permission
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestGuard(t *testing.T) {
+	install := t.TempDir()
+	other := t.TempDir()
+	g := NewGuard(install)
+
+	if err := g.CheckWrite(filepath.Join(install, "1.0.0", "bin")); err != nil {
+		t.Errorf("expected install dir to be writable, got %v", err)
+	}
+	if err := g.CheckHost("nodejs.org"); !errors.Is(err, ErrDenied) {
+		t.Errorf("expected network to be denied before Grant, got %v", err)
+	}
+
+	g.Grant(&Permissions{Network: []string{"nodejs.org", "*.github.com"}, Writable: []string{other}})
+	for host, allowed := range map[string]bool{
+		"nodejs.org":                    true,
+		"NODEJS.org":                    true,
+		"objects.github.com":            true,
+		"github.com":                    false,
+		"evil.com":                      false,
+		"nodejs.org.evil.com":           false,
+		"raw.githubusercontent.com.com": false,
+	} {
+		if err := g.CheckHost(host); (err == nil) != allowed {
+			t.Errorf("CheckHost(%s) = %v, expected allowed %v", host, err, allowed)
+		}
+	}
+	if err := g.CheckWrite(filepath.Join(other, "file")); err != nil {
+		t.Errorf("expected declared path to be writable, got %v", err)
+	}
+	if err := g.CheckWrite(filepath.Join(install, "..", "escape")); !errors.Is(err, ErrDenied) {
+		t.Errorf("expected write outside the install dir to be denied, got %v", err)
+	}
+	if err := g.CheckExec("ls"); !errors.Is(err, ErrDenied) {
+		t.Errorf("expected exec to be denied, got %v", err)
+	}
+
+	if err := g.CheckRead(filepath.Join(other, "file")); err != nil {
+		t.Errorf("expected declared writable path to be readable, got %v", err)
+	}
+
+	g.Grant(nil)
+	if err := g.CheckHost("nodejs.org"); err != nil {
+		t.Errorf("expected a plugin declaring no permissions to stay unrestricted, got %v", err)
+	}
+
+	g.Grant(&Permissions{})
+	if err := g.CheckExec("ls"); !errors.Is(err, ErrDenied) {
+		t.Errorf("expected an empty declaration to get the default permissions, got %v", err)
+	}
+	if err := g.CheckRead(filepath.Join(other, "file")); !errors.Is(err, ErrDenied) {
+		t.Errorf("expected reads outside the default paths to be denied, got %v", err)
+	}
+	g.AllowRead(filepath.Join(other, ".nvmrc"))
+	if err := g.CheckRead(filepath.Join(other, ".nvmrc")); err != nil {
+		t.Errorf("expected allowed file to be readable, got %v", err)
+	}
+
+	g.Grant(&Permissions{Unrestricted: true})
+	if err := g.CheckExec("ls"); err != nil {
+		t.Errorf("expected a plugin declaring %q to be unrestricted, got %v", All, err)
+	}
+	if err := g.CheckRead(filepath.Join(other, "file")); err != nil {
+		t.Errorf("expected a plugin declaring %q to read anywhere, got %v", All, err)
+	}
+	if err := g.CheckConfined(filepath.Join(other, "file")); !errors.Is(err, ErrDenied) {
+		t.Errorf("expected confinement to hold for an unrestricted plugin, got %v", err)
+	}
+	if err := g.CheckConfined(filepath.Join(install, "file")); err != nil {
+		t.Errorf("expected install dir to be within the confinement, got %v", err)
+	}
+}
+
+func TestGuardSymlink(t *testing.T) {
+	install := t.TempDir()
+	outside := t.TempDir()
+	link := filepath.Join(install, "link")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	g := NewGuard(install)
+	g.Grant(&Permissions{})
+	if err := g.CheckWrite(filepath.Join(link, "file")); !errors.Is(err, ErrDenied) {
+		t.Errorf("expected write through a symlink out of the install dir to be denied, got %v", err)
+	}
+}
+
+func TestWidened(t *testing.T) {
+	previous := &Permissions{Network: []string{"*.nodejs.org"}, Writable: []string{"~/.npmrc"}}
+	next := &Permissions{Network: []string{"dist.nodejs.org", "github.com"}, Writable: []string{"~/.npmrc"}, Exec: true}
+	expected := []string{"network: github.com", "exec: run processes"}
+	if got := Widened(previous, next); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := Widened(next, &Permissions{Network: []string{"github.com"}}); len(got) != 0 {
+		t.Errorf("expected narrowed permissions not to be widened, got %v", got)
+	}
+	if got := Widened(&Permissions{Unrestricted: true}, next); len(got) != 0 {
+		t.Errorf("expected nothing to widen from an unrestricted plugin, got %v", got)
+	}
+	if got := Widened(nil, next); len(got) != 0 {
+		t.Errorf("expected nothing to widen from a plugin declaring no permissions, got %v", got)
+	}
+	if got := Widened(previous, nil); len(got) != 1 {
+		t.Errorf("expected dropping declarations to widen to unrestricted access, got %v", got)
+	}
+	if got := Widened(previous, &Permissions{Unrestricted: true}); len(got) != 1 {
+		t.Errorf("expected opting in to unrestricted access to widen, got %v", got)
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	install := t.TempDir()
+	outside := t.TempDir()
+
+	L := lua.NewState()
+	defer L.Close()
+	g := NewGuard(install)
+	g.Grant(&Permissions{})
+	Attach(L, g)
+	defer Detach(L)
+	Sandbox(L)
+
+	allowed := filepath.ToSlash(filepath.Join(install, "file"))
+	if err := L.DoString(`
+		local f = assert(io.open("` + allowed + `", "w"))
+		f:write("ok")
+		f:close()
+		assert(io.open("` + allowed + `", "r")):close()
+		os.remove("` + allowed + `")
+	`); err != nil {
+		t.Fatalf("expected install dir to be writable, got %v", err)
+	}
+
+	denied := filepath.ToSlash(filepath.Join(outside, "file"))
+	if err := os.WriteFile(denied, []byte("return 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, script := range map[string]string{
+		"os.execute": `os.execute("echo hi")`,
+		"io.popen":   `io.popen("echo hi")`,
+		"io.open":    `io.open("` + denied + `", "a")`,
+		"io.lines":   `io.lines("` + denied + `")`,
+		"dofile":     `dofile("` + denied + `")`,
+		"loadfile":   `loadfile("` + denied + `")`,
+		"os.remove":  `os.remove("` + denied + `")`,
+		"os.rename":  `os.rename("` + allowed + `", "` + denied + `")`,
+		"os.exit":    `os.exit(1)`,
+	} {
+		err := L.DoString(script)
+		if err == nil || !strings.Contains(err.Error(), name) || !strings.Contains(err.Error(), ErrDenied.Error()) {
+			t.Errorf("expected %s to be denied, got %v", name, err)
+		}
+	}
+	if err := L.DoString(`io.open("` + denied + `", "r")`); err == nil || !strings.Contains(err.Error(), ErrDenied.Error()) {
+		t.Errorf("expected reading outside the install dir to be denied, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package permission
+
+import (
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Sandbox replaces the functions of the base, os and io libraries of L which read or write
+// files, run processes or exit, with ones checking the guard of L first. It must be called
+// after the libraries are opened and before any plugin code runs.
+func Sandbox(L *lua.LState) {
+	guardLib(L, lua.BaseLibName, map[string]func(L *lua.LState) error{
+		"dofile":   checkReadArg,
+		"loadfile": checkReadArg,
+	})
+	guardLib(L, lua.OsLibName, map[string]func(L *lua.LState) error{
+		"execute": func(L *lua.LState) error {
+			return Lookup(L).CheckExec(L.OptString(1, ""))
+		},
+		"remove": func(L *lua.LState) error {
+			return Lookup(L).CheckWrite(L.CheckString(1))
+		},
+		"rename": func(L *lua.LState) error {
+			g := Lookup(L)
+			if err := g.CheckWrite(L.CheckString(1)); err != nil {
+				return err
+			}
+			return g.CheckWrite(L.CheckString(2))
+		},
+		"exit": func(L *lua.LState) error {
+			if Lookup(L).Unrestricted() {
+				return nil
+			}
+			return ErrDenied
+		},
+	})
+	guardLib(L, lua.IoLibName, map[string]func(L *lua.LState) error{
+		"open": func(L *lua.LState) error {
+			if mode := L.OptString(2, "r"); strings.ContainsAny(mode, "wa+") {
+				return Lookup(L).CheckWrite(L.CheckString(1))
+			}
+			return Lookup(L).CheckRead(L.CheckString(1))
+		},
+		"lines": checkReadArg,
+		"input": checkReadArg,
+		"output": func(L *lua.LState) error {
+			if path, ok := L.Get(1).(lua.LString); ok {
+				return Lookup(L).CheckWrite(string(path))
+			}
+			return nil
+		},
+		"popen": func(L *lua.LState) error {
+			return Lookup(L).CheckExec(L.CheckString(1))
+		},
+	})
+}
+
+// checkReadArg checks reading the file named by the first argument, if any, which
+// otherwise defaults to the standard input.
+func checkReadArg(L *lua.LState) error {
+	if path, ok := L.Get(1).(lua.LString); ok {
+		return Lookup(L).CheckRead(string(path))
+	}
+	return nil
+}
+
+// guardLib wraps the functions of the library named lib with their checks.
+func guardLib(L *lua.LState, lib string, checks map[string]func(L *lua.LState) error) {
+	table := L.G.Global
+	if lib != lua.BaseLibName {
+		var ok bool
+		if table, ok = L.GetGlobal(lib).(*lua.LTable); !ok {
+			return
+		}
+	}
+	for name, check := range checks {
+		fn, ok := table.RawGetString(name).(*lua.LFunction)
+		if !ok || fn.GFunction == nil {
+			continue
+		}
+		original, check, qualified := fn.GFunction, check, name
+		if lib != lua.BaseLibName {
+			qualified = lib + "." + name
+		}
+		table.RawSetString(name, L.NewFunction(func(L *lua.LState) int {
+			if err := check(L); err != nil {
+				L.RaiseError("%s: %s", qualified, err.Error())
+				return 0
+			}
+			return original(L)
+		}))
+	}
+}
@@ -17,10 +17,12 @@
 package luai
 
 import (
+	"context"
 	_ "embed"
 	"strings"
 
 	"github.com/version-fox/vfox/internal/plugin/luai/module"
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
 	lua "github.com/yuin/gopher-lua"
 )
 
@@ -45,6 +47,10 @@ func (vm *LuaVM) Prepare(options *module.PreloadOptions) error {
 	}
 
 	if options != nil {
+		if options.Guard != nil {
+			permission.Attach(vm.Instance, options.Guard)
+			permission.Sandbox(vm.Instance)
+		}
 		module.Preload(vm.Instance, options)
 	}
 
@@ -63,7 +69,9 @@ func (vm *LuaVM) ReturnedValue() *lua.LTable {
 	return table
 }
 
-func (vm *LuaVM) CallFunction(pluginObj *lua.LTable, funcName string, _args ...lua.LValue) (*lua.LTable, error) {
+// CallFunction calls the function funcName of pluginObj, which is aborted when ctx is done.
+// Requests of the http module made by the function carry ctx as well.
+func (vm *LuaVM) CallFunction(ctx context.Context, pluginObj *lua.LTable, funcName string, _args ...lua.LValue) (*lua.LTable, error) {
 	function := pluginObj.RawGetString(funcName)
 
 	// In Lua, when a function is called with colon syntax (object:method()),
@@ -72,6 +80,10 @@ func (vm *LuaVM) CallFunction(pluginObj *lua.LTable, funcName string, _args ...l
 	// and it's being passed as the first argument to the Lua function to simulate this behavior.
 	args := append([]lua.LValue{pluginObj}, _args...)
 
+	if ctx != nil {
+		vm.Instance.SetContext(ctx)
+		defer vm.Instance.RemoveContext()
+	}
 	if err := vm.Instance.CallByParam(lua.P{
 		Fn:      function.(*lua.LFunction),
 		NRet:    1,
@@ -84,5 +96,6 @@ func (vm *LuaVM) CallFunction(pluginObj *lua.LTable, funcName string, _args ...l
 }
 
 func (vm *LuaVM) Close() {
+	permission.Detach(vm.Instance)
 	vm.Instance.Close()
 }
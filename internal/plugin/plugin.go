@@ -25,6 +25,7 @@ import (
 
 	"github.com/pterm/pterm"
 	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/plugin/luai/permission"
 	"github.com/version-fox/vfox/internal/shared/util"
 )
 
@@ -46,6 +47,8 @@ type (
 		MinRuntimeVersion string   `json:"minRuntimeVersion"`
 		Notes             []string `json:"notes"`
 		LegacyFilenames   []string `json:"legacyFilenames"`
+		// Permissions is nil for plugins declaring none, which are unrestricted (deprecated).
+		Permissions *permission.Permissions `json:"permissions"`
 	}
 
 	// Plugin is the interface that all plugins must implement.
@@ -1,6 +1,10 @@
 package plugin_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -66,6 +70,10 @@ func TestNewPluginWithMain(t *testing.T) {
 		if plug.MinRuntimeVersion != "0.2.2" {
 			t.Errorf("expected min runtime version '0.2.2', got '%s'", plug.MinRuntimeVersion)
 		}
+
+		if plug.Permissions != nil {
+			t.Errorf("expected no permissions, got %+v", plug.Permissions)
+		}
 	})
 
 	testHookFunc(t, func() (*internal.Manager, *plugin.Wrapper, error) {
@@ -141,6 +149,61 @@ func TestNewLuaPluginWithMetadataAndHooks(t *testing.T) {
 	})
 }
 
+// undeclaredPlugin is written like the plugins predating PLUGIN.permissions.
+const undeclaredPlugin = `
+PLUGIN = { name = "undeclared", version = "0.0.1" }
+
+function PLUGIN:Available(ctx)
+    return {}
+end
+
+function PLUGIN:EnvKeys(ctx)
+    return {}
+end
+
+function PLUGIN:PreInstall(ctx)
+    local http = require("http")
+    local resp, err = http.get({ url = "%s" })
+    if err ~= nil then
+        error(err)
+    end
+    return { version = resp.body }
+end
+`
+
+func TestUndeclaredPermissions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("1.0.0"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.lua"), []byte(strings.Replace(undeclaredPlugin, "%s", server.URL, 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager, err := internal.NewSdkManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+
+	plug, err := plugin.CreatePlugin(dir, manager.RuntimeEnvContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plug.Close()
+	if plug.Permissions != nil {
+		t.Errorf("expected no permissions, got %+v", plug.Permissions)
+	}
+	result, err := plug.PreInstall(&plugin.PreInstallHookCtx{Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("expected a plugin declaring no permissions to reach any host, got %v", err)
+	}
+	if result.Version != "1.0.0" {
+		t.Errorf("expected version 1.0.0, got %s", result.Version)
+	}
+}
+
 func TestInvalidPluginName(t *testing.T) {
 	teardownSuite := setupSuite(t)
 	defer teardownSuite(t)
@@ -0,0 +1,95 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package release
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/version-fox/vfox/internal/shared/semver"
+	"github.com/version-fox/vfox/internal/shared/util"
+)
+
+// ErrNoPreviousBinary is returned by Rollback when no upgrade was made.
+var ErrNoPreviousBinary = errors.New("no previous vfox binary to roll back to")
+
+// PreviousPath returns where the binary replaced by the last upgrade is kept.
+func PreviousPath(exePath string) string {
+	dir, name := filepath.Split(exePath)
+	return filepath.Join(dir, "."+name+".previous")
+}
+
+// Replace installs newExePath at exePath and keeps the current binary at PreviousPath.
+// Renaming works for a running binary on every platform, so no helper process is needed.
+func Replace(exePath, newExePath string) error {
+	if err := os.Chmod(newExePath, 0755); err != nil {
+		return fmt.Errorf("make executable: %w", err)
+	}
+	previous := PreviousPath(exePath)
+	_ = os.Remove(previous)
+	if err := os.Rename(exePath, previous); err != nil {
+		return fmt.Errorf("back up current binary: %w", err)
+	}
+	if err := os.Rename(newExePath, exePath); err != nil {
+		_ = os.Rename(previous, exePath)
+		return fmt.Errorf("replace binary: %w", err)
+	}
+	return nil
+}
+
+// Rollback swaps the binary at exePath with the one kept by the last upgrade,
+// so that rolling back twice restores the upgraded binary.
+func Rollback(exePath string) error {
+	previous := PreviousPath(exePath)
+	if _, err := os.Stat(previous); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNoPreviousBinary
+		}
+		return err
+	}
+	swap := exePath + ".rollback-" + strconv.Itoa(os.Getpid())
+	if err := os.Rename(exePath, swap); err != nil {
+		return fmt.Errorf("move current binary: %w", err)
+	}
+	if err := os.Rename(previous, exePath); err != nil {
+		_ = os.Rename(swap, exePath)
+		return fmt.Errorf("restore previous binary: %w", err)
+	}
+	if err := os.Rename(swap, previous); err != nil {
+		return fmt.Errorf("keep current binary: %w", err)
+	}
+	return nil
+}
+
+// IsNewer reports whether version is newer than current. A pre-release, such as
+// 1.1.0-beta.1, is older than the release of the same version, and numeric
+// pre-release identifiers compare by value, so beta.10 is newer than beta.9.
+func IsNewer(version, current string) bool {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return false
+	}
+	c, err := semver.Parse(current)
+	if err != nil {
+		return util.CompareVersion(strings.TrimPrefix(version, "v"), strings.TrimPrefix(current, "v")) > 0
+	}
+	return v.Compare(c) > 0
+}
@@ -0,0 +1,257 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package release finds vfox releases in a release manifest, downloads and verifies
+// them, and swaps the running binary while keeping the previous one for rollbacks.
+package release
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+)
+
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+
+	githubReleasesURL = "https://api.github.com/repos/version-fox/vfox/releases"
+	checksumsAsset    = "checksums.txt"
+)
+
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Manifest lists the latest release of every channel.
+//
+//	{
+//	  "channels": {
+//	    "stable": {
+//	      "version": "v1.0.11",
+//	      "assets": [{"name": "vfox_1.0.11_linux_x86_64.tar.gz", "url": "https://...", "sha256": "..."}]
+//	    }
+//	  }
+//	}
+type Manifest struct {
+	Channels map[string]*Release `json:"channels"`
+}
+
+// Release is a version of vfox and its archives.
+type Release struct {
+	Version  string   `json:"version"`
+	NotesURL string   `json:"notesUrl,omitempty"`
+	Assets   []*Asset `json:"assets"`
+	// ChecksumsURL is a file in sha256sum format, used for assets without a Sha256.
+	ChecksumsURL string `json:"checksumsUrl,omitempty"`
+}
+
+// Asset is a downloadable archive of a release.
+type Asset struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Sha256 string `json:"sha256,omitempty"`
+}
+
+// Channel returns the latest release of channel.
+func (m *Manifest) Channel(channel string) (*Release, error) {
+	if channel == "" {
+		channel = ChannelStable
+	}
+	r, ok := m.Channels[channel]
+	if !ok || r == nil || r.Version == "" {
+		return nil, fmt.Errorf("no release in channel %s", channel)
+	}
+	return r, nil
+}
+
+// AssetName returns the archive name of version for the given platform, as published by goreleaser.
+func AssetName(version, goos, goarch string) string {
+	osType := strings.ToLower(goos)
+	if osType == "darwin" {
+		osType = "macos"
+	}
+	archType := goarch
+	if archType == "arm64" {
+		archType = "aarch64"
+	}
+	if archType == "amd64" {
+		archType = "x86_64"
+	}
+	extName := "tar.gz"
+	if osType == "windows" {
+		extName = "zip"
+	}
+	return fmt.Sprintf("vfox_%s_%s_%s.%s", strings.TrimPrefix(version, "v"), osType, archType, extName)
+}
+
+// Asset returns the archive of the release for the given platform.
+func (r *Release) Asset(goos, goarch string) (*Asset, error) {
+	name := AssetName(r.Version, goos, goarch)
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return nil, fmt.Errorf("release %s has no archive %s", r.Version, name)
+}
+
+// CurrentAsset returns the archive of the release for the running platform.
+func (r *Release) CurrentAsset() (*Asset, error) {
+	return r.Asset(runtime.GOOS, runtime.GOARCH)
+}
+
+// FetchManifest downloads the manifest at url, or builds one from GitHub releases if url is empty.
+func FetchManifest(client *http.Client, url string) (*Manifest, error) {
+	if url == "" {
+		return fetchGithubManifest(client)
+	}
+	manifest := &Manifest{}
+	if err := getJSON(client, url, manifest); err != nil {
+		return nil, fmt.Errorf("fetch release manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+type githubRelease struct {
+	TagName    string `json:"tag_name"`
+	HtmlURL    string `json:"html_url"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	Assets     []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func fetchGithubManifest(client *http.Client) (*Manifest, error) {
+	var releases []*githubRelease
+	if err := getJSON(client, githubReleasesURL, &releases); err != nil {
+		return nil, fmt.Errorf("fetch GitHub releases: %w", err)
+	}
+	return manifestFromGithub(releases), nil
+}
+
+// manifestFromGithub picks the latest releases, newest first as returned by the API.
+// Stable is the latest full release, and beta the latest release including pre-releases.
+func manifestFromGithub(releases []*githubRelease) *Manifest {
+	manifest := &Manifest{Channels: make(map[string]*Release)}
+	for _, gr := range releases {
+		if gr.Draft {
+			continue
+		}
+		r := &Release{Version: gr.TagName, NotesURL: gr.HtmlURL}
+		for _, a := range gr.Assets {
+			if a.Name == checksumsAsset {
+				r.ChecksumsURL = a.URL
+				continue
+			}
+			r.Assets = append(r.Assets, &Asset{Name: a.Name, URL: a.URL})
+		}
+		if _, ok := manifest.Channels[ChannelBeta]; !ok {
+			manifest.Channels[ChannelBeta] = r
+		}
+		if _, ok := manifest.Channels[ChannelStable]; !ok && !gr.Prerelease {
+			manifest.Channels[ChannelStable] = r
+		}
+	}
+	return manifest
+}
+
+// Checksum returns the expected sha256 of asset, from the asset itself or the checksums file.
+func (r *Release) Checksum(client *http.Client, asset *Asset) (string, error) {
+	if asset.Sha256 != "" {
+		return strings.ToLower(asset.Sha256), nil
+	}
+	if r.ChecksumsURL == "" {
+		return "", fmt.Errorf("release %s provides no checksum for %s", r.Version, asset.Name)
+	}
+	body, err := get(client, r.ChecksumsURL)
+	if err != nil {
+		return "", fmt.Errorf("fetch checksums: %w", err)
+	}
+	defer body.Close()
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset.Name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return "", fmt.Errorf("read checksums: %w", err)
+	}
+	return "", fmt.Errorf("checksums of release %s do not list %s", r.Version, asset.Name)
+}
+
+// Download saves asset to dest and verifies it against its checksum.
+// Nothing is left at dest unless the archive is verified.
+func (r *Release) Download(client *http.Client, asset *Asset, dest string) error {
+	expected, err := r.Checksum(client, asset)
+	if err != nil {
+		return err
+	}
+	body, err := get(client, asset.URL)
+	if err != nil {
+		return fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	defer body.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, hash), body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dest)
+		return fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		_ = os.Remove(dest)
+		return fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, asset.Name, expected, actual)
+	}
+	return nil
+}
+
+func get(client *http.Client, url string) (io.ReadCloser, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: status code %d", url, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+func getJSON(client *http.Client, url string, v any) error {
+	body, err := get(client, url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(v)
+}
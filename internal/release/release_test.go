@@ -0,0 +1,165 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifestFromGithub(t *testing.T) {
+	var releases []*githubRelease
+	data := `[
+		{"tag_name": "v1.1.0-beta.1", "prerelease": true},
+		{"tag_name": "v1.0.12", "draft": true},
+		{"tag_name": "v1.0.11", "assets": [
+			{"name": "checksums.txt", "browser_download_url": "https://example.com/checksums.txt"},
+			{"name": "vfox_1.0.11_linux_x86_64.tar.gz", "browser_download_url": "https://example.com/vfox.tar.gz"}
+		]},
+		{"tag_name": "v1.0.10"}
+	]`
+	if err := json.Unmarshal([]byte(data), &releases); err != nil {
+		t.Fatal(err)
+	}
+	manifest := manifestFromGithub(releases)
+
+	beta, err := manifest.Channel(ChannelBeta)
+	if err != nil || beta.Version != "v1.1.0-beta.1" {
+		t.Errorf("Expected beta v1.1.0-beta.1, got %+v, %v", beta, err)
+	}
+	stable, err := manifest.Channel("")
+	if err != nil || stable.Version != "v1.0.11" {
+		t.Fatalf("Expected stable v1.0.11, got %+v, %v", stable, err)
+	}
+	if stable.ChecksumsURL != "https://example.com/checksums.txt" || len(stable.Assets) != 1 {
+		t.Errorf("Expected the checksums file to be split from the assets, got %+v", stable)
+	}
+	if _, err = stable.Asset("linux", "amd64"); err != nil {
+		t.Errorf("Expected the linux archive, got %v", err)
+	}
+	if _, err = manifest.Channel("nightly"); err == nil {
+		t.Errorf("Expected an unknown channel to fail")
+	}
+}
+
+func TestDownload(t *testing.T) {
+	archive := []byte("archive content")
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/archive.tar.gz":
+			_, _ = w.Write(archive)
+		case "/checksums.txt":
+			_, _ = w.Write([]byte("0000  other.tar.gz\n" + checksum + "  archive.tar.gz\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	asset := &Asset{Name: "archive.tar.gz", URL: server.URL + "/archive.tar.gz"}
+	dest := filepath.Join(t.TempDir(), asset.Name)
+
+	t.Run("ChecksumsFile", func(t *testing.T) {
+		r := &Release{Version: "v1.0.0", ChecksumsURL: server.URL + "/checksums.txt"}
+		if err := r.Download(server.Client(), asset, dest); err != nil {
+			t.Fatalf("Download failed: %v", err)
+		}
+		if content, _ := os.ReadFile(dest); string(content) != string(archive) {
+			t.Errorf("Unexpected content %q", content)
+		}
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		_ = os.Remove(dest)
+		r := &Release{Version: "v1.0.0"}
+		bad := *asset
+		bad.Sha256 = "deadbeef"
+		if err := r.Download(server.Client(), &bad, dest); !errors.Is(err, ErrChecksumMismatch) {
+			t.Fatalf("Expected a checksum mismatch, got %v", err)
+		}
+		if _, err := os.Stat(dest); !os.IsNotExist(err) {
+			t.Errorf("Expected the unverified archive to be removed")
+		}
+	})
+
+	t.Run("NoChecksum", func(t *testing.T) {
+		r := &Release{Version: "v1.0.0"}
+		if err := r.Download(server.Client(), asset, dest); err == nil {
+			t.Errorf("Expected a release without checksums to be refused")
+		}
+	})
+}
+
+func TestReplaceAndRollback(t *testing.T) {
+	dir := t.TempDir()
+	exePath := filepath.Join(dir, "vfox")
+	newExePath := filepath.Join(dir, "new")
+	_ = os.WriteFile(exePath, []byte("old"), 0755)
+	_ = os.WriteFile(newExePath, []byte("new"), 0644)
+
+	if err := Rollback(exePath); !errors.Is(err, ErrNoPreviousBinary) {
+		t.Fatalf("Expected no previous binary, got %v", err)
+	}
+	if err := Replace(exePath, newExePath); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	assertContent := func(path, want string) {
+		t.Helper()
+		if content, _ := os.ReadFile(path); string(content) != want {
+			t.Errorf("Expected %s to contain %q, got %q", path, want, content)
+		}
+	}
+	assertContent(exePath, "new")
+	assertContent(PreviousPath(exePath), "old")
+
+	if err := Rollback(exePath); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	assertContent(exePath, "old")
+	assertContent(PreviousPath(exePath), "new")
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		version, current string
+		want             bool
+	}{
+		{"v1.0.12", "v1.0.11", true},
+		{"v1.0.11", "v1.0.11", false},
+		{"v1.0.10", "v1.0.11", false},
+		{"v1.1.0-beta.1", "v1.0.11", true},
+		{"v1.1.0", "v1.1.0-beta.1", true},
+		{"v1.1.0-beta.1", "v1.1.0", false},
+		{"v1.1.0-beta.2", "v1.1.0-beta.1", true},
+		{"v1.1.0-beta.10", "v1.1.0-beta.9", true},
+		{"v1.1.0-beta.9", "v1.1.0-beta.10", false},
+		{"v1.1.0-rc.1", "v1.1.0-beta.10", true},
+	}
+	for _, tt := range tests {
+		if got := IsNewer(tt.version, tt.current); got != tt.want {
+			t.Errorf("IsNewer(%s, %s) = %v, want %v", tt.version, tt.current, got, tt.want)
+		}
+	}
+}
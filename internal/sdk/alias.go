@@ -0,0 +1,156 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/pathmeta"
+	"github.com/version-fox/vfox/internal/shared/logger"
+)
+
+// aliasDirName is the directory under the user home holding one alias file per SDK.
+const aliasDirName = "aliases"
+
+// Alias is a user-defined name for a version of an SDK, e.g. work -> 17.0.9-tem.
+type Alias struct {
+	Name    string  `json:"name" yaml:"name"`
+	Version Version `json:"version" yaml:"version"`
+}
+
+func aliasRecord(envContext *env.RuntimeEnvContext, sdkName string) (*pathmeta.FileRecord, error) {
+	path := filepath.Join(envContext.PathMeta.User.Home, aliasDirName, strings.ToLower(sdkName))
+	return pathmeta.NewFileRecord(path)
+}
+
+// ValidateAlias reports why name cannot be used as an alias.
+func ValidateAlias(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("alias name is required")
+	case name == "latest":
+		return fmt.Errorf("%s is reserved", name)
+	case strings.ContainsAny(name, " \t@/\\"):
+		return fmt.Errorf("alias %q must not contain spaces, '@' or path separators", name)
+	case looksLikeVersion(name):
+		return fmt.Errorf("alias %q looks like a version, which it would hide", name)
+	}
+	return nil
+}
+
+// looksLikeVersion reports whether name starts like a version, with a digit or "v" and a digit.
+func looksLikeVersion(name string) bool {
+	name = strings.TrimPrefix(name, "v")
+	return name != "" && name[0] >= '0' && name[0] <= '9'
+}
+
+// LoadAliases returns the aliases of the SDK sdkName, sorted by name.
+func LoadAliases(envContext *env.RuntimeEnvContext, sdkName string) ([]*Alias, error) {
+	record, err := aliasRecord(envContext, sdkName)
+	if err != nil {
+		return nil, err
+	}
+	aliases := make([]*Alias, 0, len(record.Record))
+	for name, version := range record.Record {
+		aliases = append(aliases, &Alias{Name: name, Version: Version(version)})
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Name < aliases[j].Name
+	})
+	return aliases, nil
+}
+
+// AliasedSdks returns the names of the SDKs that have aliases, sorted by name.
+func AliasedSdks(envContext *env.RuntimeEnvContext) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(envContext.PathMeta.User.Home, aliasDirName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SetAlias points the alias name of the SDK sdkName to version.
+func SetAlias(envContext *env.RuntimeEnvContext, sdkName, name string, version Version) error {
+	if err := ValidateAlias(name); err != nil {
+		return err
+	}
+	if version == "" || strings.ContainsAny(string(version), " \t") {
+		return fmt.Errorf("invalid version %q", version)
+	}
+	record, err := aliasRecord(envContext, sdkName)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(record.Path), pathmeta.ReadWriteAuth); err != nil {
+		return err
+	}
+	record.Record[name] = string(version)
+	return record.Save()
+}
+
+// RemoveAlias deletes the alias name of the SDK sdkName.
+func RemoveAlias(envContext *env.RuntimeEnvContext, sdkName, name string) error {
+	record, err := aliasRecord(envContext, sdkName)
+	if err != nil {
+		return err
+	}
+	if _, ok := record.Record[name]; !ok {
+		return fmt.Errorf("alias %s not found for %s", name, sdkName)
+	}
+	delete(record.Record, name)
+	if len(record.Record) == 0 {
+		return os.Remove(record.Path)
+	}
+	return record.Save()
+}
+
+// ExpandAlias returns the version the alias version points to, or version
+// itself if it is not an alias of the SDK sdkName.
+func ExpandAlias(envContext *env.RuntimeEnvContext, sdkName string, version Version) Version {
+	if version == "" || envContext == nil || envContext.PathMeta == nil {
+		return version
+	}
+	record, err := aliasRecord(envContext, sdkName)
+	if err != nil {
+		return version
+	}
+	if target, ok := record.Record[string(version)]; ok {
+		logger.Debugf("Expanded alias %s@%s to %s\n", sdkName, version, target)
+		return Version(target)
+	}
+	return version
+}
+
+// ExpandAlias expands version with the aliases of this SDK, see ExpandAlias.
+func (b *impl) ExpandAlias(version Version) Version {
+	return ExpandAlias(b.envContext, b.Name, version)
+}
@@ -0,0 +1,84 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/version-fox/vfox/internal/env"
+	"github.com/version-fox/vfox/internal/pathmeta"
+)
+
+func TestAliases(t *testing.T) {
+	root := t.TempDir()
+	envContext := &env.RuntimeEnvContext{
+		PathMeta: &pathmeta.PathMeta{User: pathmeta.UserPaths{Home: filepath.Join(root, "home")}},
+	}
+
+	if err := SetAlias(envContext, "Java", "work", "17.0.9-tem"); err != nil {
+		t.Fatalf("SetAlias() failed: %v", err)
+	}
+	if err := SetAlias(envContext, "java", "bad alias", "17"); err == nil {
+		t.Fatal("expected an alias with spaces to be rejected")
+	}
+	for _, name := range []string{"20", "18.0.0", "v20", "21-tem"} {
+		if err := SetAlias(envContext, "java", name, "17"); err == nil {
+			t.Errorf("expected alias %s, which looks like a version, to be rejected", name)
+		}
+	}
+	if got := ExpandAlias(envContext, "java", "work"); got != "17.0.9-tem" {
+		t.Errorf("ExpandAlias(work) = %s", got)
+	}
+	if got := ExpandAlias(envContext, "java", "21"); got != "21" {
+		t.Errorf("ExpandAlias(21) = %s, expected versions to be kept", got)
+	}
+
+	names, err := AliasedSdks(envContext)
+	if err != nil || !reflect.DeepEqual(names, []string{"java"}) {
+		t.Fatalf("AliasedSdks() = %v, %v", names, err)
+	}
+
+	installPath := filepath.Join(root, "installs", "java")
+	if err = os.MkdirAll(filepath.Join(installPath, "v-17.0.9-tem"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	b := &impl{Name: "java", InstallPath: installPath, envContext: envContext}
+	for _, version := range []Version{"work", "21", ""} {
+		if got, want := b.ExpandAlias(version), ExpandAlias(envContext, "java", version); got != want {
+			t.Errorf("(*impl).ExpandAlias(%q) = %s, expected %s like ExpandAlias", version, got, want)
+		}
+	}
+	if !b.CheckRuntimeExist(b.ExpandAlias("work")) {
+		t.Error("expected the alias to resolve to the installed version")
+	}
+	if b.CheckRuntimeExist("work") {
+		t.Error("expected aliases to be expanded only where user versions are resolved")
+	}
+
+	if err = RemoveAlias(envContext, "java", "work"); err != nil {
+		t.Fatalf("RemoveAlias() failed: %v", err)
+	}
+	if aliases, _ := LoadAliases(envContext, "java"); len(aliases) != 0 {
+		t.Errorf("expected no aliases left, got %v", aliases)
+	}
+	if err = RemoveAlias(envContext, "java", "work"); err == nil {
+		t.Error("expected removing a missing alias to fail")
+	}
+}
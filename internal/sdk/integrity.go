@@ -0,0 +1,202 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileManifestFilename is the name of the file manifest written into every package directory.
+const FileManifestFilename = ".vfox-files.json"
+
+// ErrFileManifestNotFound is returned for versions installed before file manifests were written.
+var ErrFileManifestNotFound = errors.New("no file manifest found")
+
+// FileManifest records the files of a package directory right after installation.
+type FileManifest struct {
+	Files []*FileManifestEntry `json:"files"`
+}
+
+// FileManifestEntry is a regular file or a symlink of a package directory.
+type FileManifestEntry struct {
+	// Path is relative to the package directory, using forward slashes.
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256,omitempty"`
+	Link   string `json:"link,omitempty"`
+}
+
+// VerifyResult lists the files of a package directory that changed since installation.
+type VerifyResult struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+// HasDrift reports whether any file changed.
+func (r *VerifyResult) HasDrift() bool {
+	return len(r.Added)+len(r.Removed)+len(r.Modified) > 0
+}
+
+// isMetadataFile reports whether rel is one of the files vfox writes into a package directory.
+func isMetadataFile(rel string) bool {
+	switch rel {
+	case FileManifestFilename, ReceiptFilename, InstallCompleteMarker:
+		return true
+	}
+	return false
+}
+
+// buildFileManifest hashes every file of the package directory dir.
+func buildFileManifest(dir string) (*FileManifest, error) {
+	manifest := &FileManifest{Files: []*FileManifestEntry{}}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if isMetadataFile(rel) {
+			return nil
+		}
+		entry := &FileManifestEntry{Path: rel}
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			if entry.Link, err = os.Readlink(p); err != nil {
+				return err
+			}
+			entry.Link = filepath.ToSlash(entry.Link)
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			entry.Size = info.Size()
+			if entry.Sha256, err = fileSha256(p); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+		manifest.Files = append(manifest.Files, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func fileSha256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeFileManifest records the files of the package directory dir.
+func writeFileManifest(dir string) error {
+	manifest, err := buildFileManifest(dir)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, FileManifestFilename), content, 0644)
+}
+
+func readFileManifest(dir string) (*FileManifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, FileManifestFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrFileManifestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest := &FileManifest{}
+	if err = json.Unmarshal(content, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// diffFileManifest compares the recorded manifest with the current one.
+func diffFileManifest(recorded, current *FileManifest) *VerifyResult {
+	result := &VerifyResult{}
+	currentFiles := make(map[string]*FileManifestEntry, len(current.Files))
+	for _, entry := range current.Files {
+		currentFiles[entry.Path] = entry
+	}
+	for _, entry := range recorded.Files {
+		now, ok := currentFiles[entry.Path]
+		if !ok {
+			result.Removed = append(result.Removed, entry.Path)
+			continue
+		}
+		delete(currentFiles, entry.Path)
+		if *now != *entry {
+			result.Modified = append(result.Modified, entry.Path)
+		}
+	}
+	for path := range currentFiles {
+		result.Added = append(result.Added, path)
+	}
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Modified)
+	return result
+}
+
+// Verify re-hashes the package directory of version and compares it with the
+// file manifest recorded at install time.
+func (b *impl) Verify(version Version) (*VerifyResult, error) {
+	if !b.CheckRuntimeExist(version) {
+		return nil, ErrRuntimeNotFound
+	}
+	dir := b.packagePath(version)
+	recorded, err := readFileManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	current, err := buildFileManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	return diffFileManifest(recorded, current), nil
+}
@@ -0,0 +1,79 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	installPath := filepath.Join(t.TempDir(), "test-sdk")
+	versionPath := filepath.Join(installPath, "v-1.0.0")
+	binDir := filepath.Join(versionPath, "test-sdk-1.0.0", "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"tool": "v1", "keep": "same", "gone": "bye"} {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := &impl{Name: "test-sdk", InstallPath: installPath}
+
+	if _, err := b.Verify("1.0.0"); !errors.Is(err, ErrFileManifestNotFound) {
+		t.Fatalf("expected ErrFileManifestNotFound, got %v", err)
+	}
+	if err := writeFileManifest(versionPath); err != nil {
+		t.Fatalf("writeFileManifest() failed: %v", err)
+	}
+	// Files written by vfox itself are never reported
+	if err := writeReceipt(versionPath, &Receipt{Name: "test-sdk"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := markInstallComplete(versionPath); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := b.Verify("1.0.0")
+	if err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	if result.HasDrift() {
+		t.Fatalf("expected no drift, got %+v", result)
+	}
+
+	_ = os.WriteFile(filepath.Join(binDir, "tool"), []byte("v2"), 0755)
+	_ = os.Remove(filepath.Join(binDir, "gone"))
+	_ = os.WriteFile(filepath.Join(binDir, "extra"), []byte("new"), 0755)
+
+	result, err = b.Verify("1.0.0")
+	if err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	want := &VerifyResult{
+		Added:    []string{"test-sdk-1.0.0/bin/extra"},
+		Removed:  []string{"test-sdk-1.0.0/bin/gone"},
+		Modified: []string{"test-sdk-1.0.0/bin/tool"},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("Verify() = %+v, want %+v", result, want)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/version-fox/vfox/internal/plugin"
+)
+
+// ReceiptFilename is the name of the receipt written into every package directory.
+const ReceiptFilename = ".vfox-receipt.json"
+
+// ErrReceiptNotFound is returned for versions installed before receipts were written.
+var ErrReceiptNotFound = errors.New("no install receipt found")
+
+// Receipt records where an installed runtime package came from.
+type Receipt struct {
+	Name          string         `json:"name" yaml:"name"`
+	Version       Version        `json:"version" yaml:"version"`
+	PluginName    string         `json:"plugin_name" yaml:"plugin_name"`
+	PluginVersion string         `json:"plugin_version" yaml:"plugin_version"`
+	VfoxVersion   string         `json:"vfox_version" yaml:"vfox_version"`
+	InstalledAt   time.Time      `json:"installed_at" yaml:"installed_at"`
+	Main          *ReceiptItem   `json:"main" yaml:"main"`
+	Additions     []*ReceiptItem `json:"additions" yaml:"additions"`
+}
+
+// ReceiptItem describes a single runtime of the package.
+type ReceiptItem struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	// Dir is the directory of the runtime, relative to the package directory.
+	Dir string `json:"dir" yaml:"dir"`
+	// Source is the URL or local path the runtime was installed from, if any.
+	Source   string `json:"source,omitempty" yaml:"source,omitempty"`
+	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	Note     string `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+func newReceiptItem(info *plugin.PreInstallPackageItem, dir string) *ReceiptItem {
+	item := &ReceiptItem{
+		Name:    info.Name,
+		Version: info.Version,
+		Dir:     dir,
+		Source:  redactSource(info.Path),
+		Note:    info.Note,
+	}
+	if checksum := info.Checksum(); checksum.Value != "" {
+		item.Checksum = checksum.Type + ":" + checksum.Value
+	}
+	return item
+}
+
+// redactSource hides credentials embedded in a download URL.
+func redactSource(source string) string {
+	u, err := url.Parse(source)
+	if err != nil || u.User == nil {
+		return source
+	}
+	return u.Redacted()
+}
+
+// newReceipt creates the receipt of version installed from mainSdk and additions.
+func (b *impl) newReceipt(version Version, mainSdk *plugin.PreInstallPackageItem, additions []*plugin.PreInstallPackageItem) *Receipt {
+	receipt := &Receipt{
+		Name:          b.Name,
+		Version:       version,
+		PluginName:    b.plugin.Name,
+		PluginVersion: b.plugin.Version,
+		VfoxVersion:   b.envContext.RuntimeVersion,
+		InstalledAt:   time.Now().UTC(),
+		Main:          newReceiptItem(mainSdk, b.runtimePathDirName(true, mainSdk)),
+		Additions:     make([]*ReceiptItem, 0, len(additions)),
+	}
+	for _, addition := range additions {
+		receipt.Additions = append(receipt.Additions, newReceiptItem(addition, b.runtimePathDirName(false, addition)))
+	}
+	return receipt
+}
+
+func writeReceipt(dir string, receipt *Receipt) error {
+	content, err := json.MarshalIndent(receipt, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ReceiptFilename), content, 0644)
+}
+
+func readReceipt(dir string) (*Receipt, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ReceiptFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrReceiptNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	receipt := &Receipt{}
+	if err = json.Unmarshal(content, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// Receipt returns the install receipt of version.
+func (b *impl) Receipt(version Version) (*Receipt, error) {
+	if !b.CheckRuntimeExist(version) {
+		return nil, ErrRuntimeNotFound
+	}
+	return readReceipt(b.packagePath(version))
+}
+
+// noteByDir maps the directory of every runtime in the receipt to its note.
+func (r *Receipt) noteByDir() map[string]string {
+	notes := make(map[string]string)
+	for _, item := range append([]*ReceiptItem{r.Main}, r.Additions...) {
+		if item != nil && item.Note != "" {
+			notes[item.Dir] = item.Note
+		}
+	}
+	return notes
+}
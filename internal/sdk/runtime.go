@@ -32,6 +32,7 @@ type Runtime struct {
 	Name    string  `json:"name"`
 	Version Version `json:"version"`
 	Path    string  `json:"path"`
+	Note    string  `json:"note,omitempty"` // optional, note from PreInstall
 }
 
 // replacePath returns a new Runtime with the Path replaced by joining the parentPath and the runtime's Name.
@@ -41,6 +42,7 @@ func (r *Runtime) replacePath(parentPath string) *Runtime {
 		Name:    r.Name,
 		Version: r.Version,
 		Path:    path,
+		Note:    r.Note,
 	}
 }
 
@@ -104,5 +106,6 @@ func convertRuntime2InstalledPackageItem(runtime *Runtime) *plugin.InstalledPack
 		Name:    runtime.Name,
 		Version: string(runtime.Version),
 		Path:    runtime.Path,
+		Note:    runtime.Note,
 	}
 }
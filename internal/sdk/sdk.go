@@ -37,6 +37,7 @@ import (
 	"github.com/version-fox/vfox/internal/pathmeta"
 	"github.com/version-fox/vfox/internal/plugin"
 	"github.com/version-fox/vfox/internal/shared/cache"
+	"github.com/version-fox/vfox/internal/shared/flock"
 	"github.com/version-fox/vfox/internal/shared/logger"
 	"github.com/version-fox/vfox/internal/shared/util"
 	"github.com/version-fox/vfox/internal/shell"
@@ -45,6 +46,11 @@ import (
 const (
 	packageInstalledPrefix = "v-"   // Prefix of path for installed SDK packages
 	additionRuntimePrefix  = "add-" // Prefix of path for additional runtime packages
+
+	// AvailableCacheFilename is the file in the plugin directory caching Available hook results.
+	AvailableCacheFilename = ".available.cache"
+	// RefreshCacheCommand is the hidden command refreshing stale caches in the background.
+	RefreshCacheCommand = "refresh-cache"
 )
 
 // Sdk interface defines the methods for managing software development kits (SDKs).
@@ -52,12 +58,17 @@ type Sdk interface {
 	Install(version Version) error                                        // Install a specific runtime of the SDK
 	Uninstall(version Version) error                                      // Uninstall a specific runtime of the SDK
 	Available(args []string) ([]*AvailableRuntimePackage, error)          // List available runtime of the SDK
+	RefreshAvailable(args []string) ([]*AvailableRuntimePackage, error)   // List available runtime of the SDK, bypassing the cache
 	EnvKeys(runtimePackage *RuntimePackage) (*env.Envs, error)            // Get environment variables for a specific runtime of the SDK
 	Use(version Version, scope env.UseScope) error                        // Use a specific runtime in a given scope
 	UseWithConfig(version Version, scope env.UseScope, unlink bool) error // Use with link configuration
 	Unuse(scopes ...env.UseScope) error                                   // Unuse the current runtime in a given scope
 	GetRuntimePackage(version Version) (*RuntimePackage, error)           // Get the runtime package for a specific version
 	CheckRuntimeExist(version Version) bool                               // Check if a specific runtime version is installed
+	Receipt(version Version) (*Receipt, error)                            // Get the install receipt of a specific runtime version
+	Verify(version Version) (*VerifyResult, error)                        // Compare a specific runtime version with its file manifest
+	ExpandAlias(version Version) Version                                  // Resolve a user-defined alias to the version it points to
+	Import(version Version, dir string) (bool, error)                     // Install the runtime package in dir, reporting false if already installed
 	InstalledList() []Version
 	ParseLegacyFile(path string) (Version, error) // Parse legacy version file to get the runtime version
 	Current() Version
@@ -105,45 +116,53 @@ func (b *impl) invokeAvailable(args []string) ([]*AvailableRuntimePackage, error
 }
 
 func (b *impl) Available(args []string) ([]*AvailableRuntimePackage, error) {
-	p := b.plugin
-	envCtx := b.envContext
-	cachePath := filepath.Join(p.InstalledPath, ".available.cache")
-	cacheDuration := envCtx.UserConfig.Cache.AvailableHookDuration
+	return b.available(args, false)
+}
+
+// RefreshAvailable invokes the Available hook bypassing the cache, and caches the result.
+func (b *impl) RefreshAvailable(args []string) ([]*AvailableRuntimePackage, error) {
+	return b.available(args, true)
+}
+
+func (b *impl) available(args []string, refresh bool) ([]*AvailableRuntimePackage, error) {
+	cacheDuration := b.envContext.UserConfig.Cache.AvailableHookDuration
 	logger.Debugf("Available hook cache duration: %v\n", cacheDuration)
-	// Cache is disabled
-	if cacheDuration == 0 {
-		return b.invokeAvailable(args)
-	}
 
 	cacheKey := strings.Join(args, "##")
 	if cacheKey == "" {
 		cacheKey = "empty"
 	}
-
-	fileCache, err := cache.NewFileCache(cachePath)
-	if err == nil {
-		cacheValue, ok := fileCache.Get(cacheKey)
-		logger.Debugf("Available hook cache key: %s, hit: %+v \n", cacheKey, ok)
-		if ok {
-			var hookResult []*AvailableRuntimePackage
-			if err = cacheValue.Unmarshal(&hookResult); err == nil {
-				return hookResult, nil
+	entry := &cache.Entry{
+		Path:     filepath.Join(b.plugin.InstalledPath, AvailableCacheFilename),
+		Key:      cacheKey,
+		Duration: cacheDuration,
+		Origin:   fmt.Sprintf("%s@%s", b.plugin.Name, b.plugin.Version),
+		Load: func() (cache.Value, error) {
+			result, err := b.invokeAvailable(args)
+			if err != nil {
+				return nil, err
 			}
-		}
+			return cache.NewValue(result)
+		},
+		Revalidate: func() {
+			cmdArgs := append([]string{RefreshCacheCommand, "available", b.Name}, args...)
+			if err := util.StartDetached(b.envContext.PathMeta.Executable, cmdArgs...); err != nil {
+				logger.Debugf("Failed to start background refresh: %v\n", err)
+			}
+		},
 	}
-	result, err := b.invokeAvailable(args)
-	if err != nil {
-		return result, err
+	get := entry.Get
+	if refresh {
+		get = entry.Refresh
 	}
-	if result == nil {
-		fileCache.Set(cacheKey, nil, cache.ExpireTime(cacheDuration))
+	value, err := get()
+	if err != nil {
+		return nil, err
 	}
-	if value, err := cache.NewValue(result); err == nil {
-		logger.Debugf("Available hook cache set\n")
-		fileCache.Set(cacheKey, value, cache.ExpireTime(cacheDuration))
-		_ = fileCache.Close()
+	var result []*AvailableRuntimePackage
+	if err = value.Unmarshal(&result); err != nil {
+		return nil, fmt.Errorf("decode available versions: %w", err)
 	}
-
 	return result, nil
 }
 
@@ -154,6 +173,16 @@ func (b *impl) Install(version Version) error {
 	label := b.Label(version)
 	logger.Debugf("Installing SDK: %s\n", label)
 
+	// Installs interrupted by a crash or kill -9 leave their staging directories behind.
+	cleanStaleStaging(b.InstallPath)
+
+	lock, err := b.lockVersion(version)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	// Another process may have installed it while we were waiting for the lock.
 	if b.CheckRuntimeExist(version) {
 		fmt.Printf("%s is already installed\n", label)
 		logger.Debugf("SDK %s already exists\n", label)
@@ -184,6 +213,13 @@ func (b *impl) Install(version Version) error {
 	// for example, latest is resolved to a specific version number.
 	label = b.Label(sdkVersion)
 	logger.Debugf("Resolved version: %s\n", sdkVersion)
+	if sdkVersion != version {
+		resolvedLock, err := b.lockVersion(sdkVersion)
+		if err != nil {
+			return err
+		}
+		defer resolvedLock.Unlock()
+	}
 	if b.CheckRuntimeExist(sdkVersion) {
 		fmt.Printf("%s is already installed\n", label)
 		logger.Debugf("SDK %s already exists after version resolution\n", label)
@@ -191,29 +227,37 @@ func (b *impl) Install(version Version) error {
 	}
 	success := false
 	newDirPath := b.packagePath(sdkVersion)
-	logger.Debugf("Installing to path: %s\n", newDirPath)
+	// The packages are downloaded into a staging directory first and moved to newDirPath
+	// before PostInstall runs, so that PostInstall sees the final paths. The complete marker
+	// is written last, so an interrupted install never looks installed.
+	b.cleanStaging(sdkVersion)
+	stagingPath := b.stagingPath(sdkVersion)
+	logger.Debugf("Installing to path: %s, staging at: %s\n", newDirPath, stagingPath)
 
 	sigs := make(chan os.Signal, 1)
 
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
 
+	// newDirPath holds no complete install, so it can be removed with the staging
+	// directory whatever stage the install reached. The version lock is held.
 	go func() {
 		_ = <-sigs
-		if !success {
-			_ = os.RemoveAll(newDirPath)
-		}
-		os.Exit(0)
+		_ = os.RemoveAll(stagingPath)
+		_ = os.RemoveAll(newDirPath)
+		os.Exit(130)
 	}()
 
-	// Delete directory after failed installation
+	// Delete staging directory and incomplete package after failed installation
 	defer func() {
 		if !success {
+			_ = os.RemoveAll(stagingPath)
 			_ = os.RemoveAll(newDirPath)
 		}
 	}()
 	installedPackage := make(map[string]*plugin.InstalledPackageItem)
 
-	path, err := b.preInstallSdk(mainSdk, filepath.Join(newDirPath, b.runtimePathDirName(true, mainSdk)))
+	path, err := b.preInstallSdk(mainSdk, filepath.Join(stagingPath, b.runtimePathDirName(true, mainSdk)))
 
 	if err != nil {
 		return err
@@ -227,7 +271,7 @@ func (b *impl) Install(version Version) error {
 	if len(installInfo.Addition) > 0 {
 		pterm.Printf("There are %d additional files that need to be downloaded...\n", len(installInfo.Addition))
 		for _, oSdk := range installInfo.Addition {
-			path, err = b.preInstallSdk(oSdk, filepath.Join(newDirPath, b.runtimePathDirName(false, oSdk)))
+			path, err = b.preInstallSdk(oSdk, filepath.Join(stagingPath, b.runtimePathDirName(false, oSdk)))
 			if err != nil {
 				return err
 			}
@@ -239,6 +283,23 @@ func (b *impl) Install(version Version) error {
 			}
 		}
 	}
+	// The receipt marks the package as incomplete until the complete marker is written.
+	if err = writeReceipt(stagingPath, b.newReceipt(sdkVersion, mainSdk, installInfo.Addition)); err != nil {
+		return fmt.Errorf("failed to write install receipt of %s: %w", label, err)
+	}
+	// An install interrupted before the marker existed may have left an incomplete package behind.
+	if err = os.RemoveAll(newDirPath); err != nil {
+		return fmt.Errorf("failed to remove the incomplete install of %s: %w", label, err)
+	}
+	logger.Debugf("Moving %s to %s\n", stagingPath, newDirPath)
+	if err = os.Rename(stagingPath, newDirPath); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", label, err)
+	}
+	for _, item := range installedPackage {
+		if rel, err := filepath.Rel(stagingPath, item.Path); err == nil {
+			item.Path = filepath.Join(newDirPath, rel)
+		}
+	}
 	postCtx := &plugin.PostInstallHookCtx{
 		RootPath: newDirPath,
 		SdkInfo:  installedPackage,
@@ -250,6 +311,12 @@ func (b *impl) Install(version Version) error {
 			return fmt.Errorf("plugin [PostInstall] method error: %w", err)
 		}
 	}
+	if err = writeFileManifest(newDirPath); err != nil {
+		return fmt.Errorf("failed to record the files of %s: %w", label, err)
+	}
+	if err = markInstallComplete(newDirPath); err != nil {
+		return fmt.Errorf("failed to mark %s as installed: %w", label, err)
+	}
 	success = true
 	pterm.Printf("Install %s success! \n", pterm.LightGreen(label))
 	logger.Debugf("SDK %s installed successfully\n", label)
@@ -267,6 +334,75 @@ func (b *impl) Install(version Version) error {
 	return nil
 }
 
+// Import installs the runtime package in dir, such as one unpacked from a bundle, as version,
+// without running any hook. It reports false if version is already installed. Like Install,
+// the package is moved into a staging directory and only swapped in once it is complete.
+func (b *impl) Import(version Version, dir string) (bool, error) {
+	label := b.Label(version)
+	logger.Debugf("Importing SDK %s from %s\n", label, dir)
+
+	cleanStaleStaging(b.InstallPath)
+	lock, err := b.lockVersion(version)
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+
+	if b.CheckRuntimeExist(version) {
+		return false, nil
+	}
+	b.cleanStaging(version)
+	stagingPath := b.stagingPath(version)
+	success := false
+	defer func() {
+		if !success {
+			_ = os.RemoveAll(stagingPath)
+		}
+	}()
+	if err = os.MkdirAll(b.InstallPath, pathmeta.ReadWriteAuth); err != nil {
+		return false, err
+	}
+	if err = util.MovePath(dir, stagingPath); err != nil {
+		return false, fmt.Errorf("failed to move %s: %w", label, err)
+	}
+	// Packages from installs predating receipts get one recording the import.
+	if _, err = readReceipt(stagingPath); errors.Is(err, ErrReceiptNotFound) {
+		mainSdk := &plugin.PreInstallPackageItem{Name: b.plugin.Name, Version: string(version)}
+		if err = writeReceipt(stagingPath, b.newReceipt(version, mainSdk, nil)); err != nil {
+			return false, fmt.Errorf("failed to write install receipt of %s: %w", label, err)
+		}
+	}
+	if !util.FileExists(filepath.Join(stagingPath, FileManifestFilename)) {
+		if err = writeFileManifest(stagingPath); err != nil {
+			return false, fmt.Errorf("failed to record the files of %s: %w", label, err)
+		}
+	}
+	if err = markInstallComplete(stagingPath); err != nil {
+		return false, fmt.Errorf("failed to mark %s as installed: %w", label, err)
+	}
+	// Only now that the package is complete, replace what an interrupted install left behind.
+	newDirPath := b.packagePath(version)
+	if err = os.RemoveAll(newDirPath); err != nil {
+		return false, fmt.Errorf("failed to remove the incomplete install of %s: %w", label, err)
+	}
+	if err = os.Rename(stagingPath, newDirPath); err != nil {
+		return false, fmt.Errorf("failed to move %s into place: %w", label, err)
+	}
+	success = true
+	return true, nil
+}
+
+// lockVersion takes the cross-process lock of version, so that vfox processes sharing
+// the install root never install or uninstall the same package at the same time.
+// Locks are always taken in the order requested version, then resolved version.
+func (b *impl) lockVersion(version Version) (*flock.Lock, error) {
+	path := versionLockPath(b.InstallPath, version)
+	logger.Debugf("Acquiring lock: %s\n", path)
+	return flock.Acquire(path, flock.DefaultTimeout, func() {
+		pterm.Printf("Waiting for another vfox process to finish with %s...\n", pterm.LightBlue(b.Label(version)))
+	})
+}
+
 func (b *impl) moveLocalFile(info *plugin.PreInstallPackageItem, targetPath string) error {
 	pterm.Printf("Moving %s to %s...\n", info.Path, targetPath)
 	if err := util.MoveFiles(info.Path, targetPath); err != nil {
@@ -364,6 +500,12 @@ func (b *impl) Uninstall(version Version) (err error) {
 	label := b.Label(version)
 	logger.Debugf("Uninstalling SDK: %s\n", label)
 
+	lock, err := b.lockVersion(version)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	if !b.CheckRuntimeExist(version) {
 		logger.Debugf("SDK %s not installed\n", label)
 		return fmt.Errorf("%s is not installed", pterm.Red(label))
@@ -688,7 +830,7 @@ func (b *impl) InstalledList() []Version {
 		return make([]Version, 0)
 	}
 	for _, d := range dir {
-		if d.IsDir() && strings.HasPrefix(d.Name(), "v-") {
+		if d.IsDir() && strings.HasPrefix(d.Name(), "v-") && isInstallComplete(filepath.Join(b.InstallPath, d.Name())) {
 			versions = append(versions, Version(strings.TrimPrefix(d.Name(), "v-")))
 		}
 	}
@@ -709,8 +851,10 @@ func (b *impl) Current() Version {
 	}
 
 	// Search for current version (with priority)
-	if version, _, ok := chain.GetToolVersion(b.Name); ok && b.CheckRuntimeExist(Version(version)) {
-		return Version(version)
+	if version, _, ok := chain.GetToolVersion(b.Name); ok {
+		if v := b.ExpandAlias(Version(version)); b.CheckRuntimeExist(v) {
+			return v
+		}
 	}
 	return ""
 }
@@ -882,6 +1026,13 @@ func (b *impl) GetRuntimePackage(version Version) (*RuntimePackage, error) {
 			}
 		}
 	}
+	// Notes are only known at install time, so they are reloaded from the receipt.
+	if receipt, err := readReceipt(versionPath); err == nil {
+		notes := receipt.noteByDir()
+		for _, item := range items {
+			item.Note = notes[filepath.Base(item.Path)]
+		}
+	}
 	main, ok := items[b.plugin.Name]
 	if !ok {
 		return nil, ErrRuntimeNotFound
@@ -903,7 +1054,7 @@ func (b *impl) GetRuntimePackage(version Version) (*RuntimePackage, error) {
 }
 
 func (b *impl) CheckRuntimeExist(version Version) bool {
-	return util.FileExists(b.packagePath(version))
+	return isInstallComplete(b.packagePath(version))
 }
 
 func (b *impl) packagePath(version Version) string {
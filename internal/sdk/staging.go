@@ -0,0 +1,159 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/version-fox/vfox/internal/shared/flock"
+	"github.com/version-fox/vfox/internal/shared/logger"
+	"github.com/version-fox/vfox/internal/shared/util"
+)
+
+const (
+	// stagingInfix separates the version from the pid in the name of a staging directory,
+	// {InstallPath}/.v-{version}.staging-{pid}
+	stagingInfix = ".staging-"
+	// InstallCompleteMarker is written into a package directory once everything,
+	// including PostInstall, succeeded.
+	InstallCompleteMarker = ".vfox-complete"
+)
+
+// stagingPath returns the directory the current process installs version into
+// before moving it to packagePath.
+func (b *impl) stagingPath(version Version) string {
+	return filepath.Join(b.InstallPath, "."+packageInstalledPrefix+string(version)+stagingInfix+strconv.Itoa(os.Getpid()))
+}
+
+func versionLockPath(installPath string, version Version) string {
+	return filepath.Join(installPath, "."+packageInstalledPrefix+string(version)+".lock")
+}
+
+// parseStagingName returns the version of a staging directory name.
+func parseStagingName(name string) (Version, bool) {
+	rest, ok := strings.CutPrefix(name, "."+packageInstalledPrefix)
+	if !ok {
+		return "", false
+	}
+	idx := strings.LastIndex(rest, stagingInfix)
+	if idx <= 0 {
+		return "", false
+	}
+	return Version(rest[:idx]), true
+}
+
+// cleanStaging removes the staging directories of version left behind by crashed
+// installs. The caller must hold the lock of version.
+func (b *impl) cleanStaging(version Version) {
+	entries, err := os.ReadDir(b.InstallPath)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if v, ok := parseStagingName(entry.Name()); ok && v == version && entry.IsDir() {
+			path := filepath.Join(b.InstallPath, entry.Name())
+			logger.Debugf("Removing stale staging directory: %s\n", path)
+			_ = os.RemoveAll(path)
+		}
+	}
+}
+
+// markInstallComplete writes the completion marker into the package directory dir.
+func markInstallComplete(dir string) error {
+	return os.WriteFile(filepath.Join(dir, InstallCompleteMarker), []byte(time.Now().UTC().Format(time.RFC3339)), 0644)
+}
+
+// isInstallComplete reports whether the package directory dir holds a complete install.
+// Installs made before the marker existed have neither the marker nor a receipt, and
+// are taken as complete.
+func isInstallComplete(dir string) bool {
+	if util.FileExists(filepath.Join(dir, InstallCompleteMarker)) {
+		return true
+	}
+	return util.FileExists(dir) && !util.FileExists(filepath.Join(dir, ReceiptFilename))
+}
+
+// CleanStaleStaging removes the staging directories of crashed installs under the
+// SDK install directories in installsRoot.
+func CleanStaleStaging(installsRoot string) {
+	roots := []string{installsRoot, filepath.Join(installsRoot, "cache")}
+	for _, root := range roots {
+		sdkDirs, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, sdkDir := range sdkDirs {
+			if sdkDir.IsDir() {
+				cleanStaleStaging(filepath.Join(root, sdkDir.Name()))
+			}
+		}
+	}
+}
+
+// cleanStaleStaging removes the staging directories of crashed installs under the
+// install directory of an SDK. A staging directory is stale when nobody holds the
+// lock of its version.
+func cleanStaleStaging(installPath string) {
+	entries, err := os.ReadDir(installPath)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		version, ok := parseStagingName(entry.Name())
+		if !ok || !entry.IsDir() {
+			continue
+		}
+		lock := flock.New(versionLockPath(installPath, version))
+		if locked, err := lock.TryLock(); err != nil || !locked {
+			continue
+		}
+		path := filepath.Join(installPath, entry.Name())
+		logger.Debugf("Removing stale staging directory: %s\n", path)
+		_ = os.RemoveAll(path)
+		_ = lock.Unlock()
+	}
+}
+
+// CleanInstallPath removes the package and staging directories left under the install
+// directory of an SDK once none of its versions is installed. Versions another process
+// holds the lock of are skipped, and the lock files themselves are kept because other
+// processes may be waiting on them.
+func CleanInstallPath(installPath string) {
+	cleanStaleStaging(installPath)
+	entries, err := os.ReadDir(installPath)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		version, ok := strings.CutPrefix(entry.Name(), packageInstalledPrefix)
+		if !ok || !entry.IsDir() {
+			continue
+		}
+		lock := flock.New(versionLockPath(installPath, Version(version)))
+		if locked, err := lock.TryLock(); err != nil || !locked {
+			continue
+		}
+		path := filepath.Join(installPath, entry.Name())
+		logger.Debugf("Removing package directory: %s\n", path)
+		_ = os.RemoveAll(path)
+		_ = lock.Unlock()
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/version-fox/vfox/internal/shared/flock"
+)
+
+func TestParseStagingName(t *testing.T) {
+	tests := []struct {
+		name    string
+		version Version
+		ok      bool
+	}{
+		{".v-20.1.0.staging-123", "20.1.0", true},
+		{".v-1.0.0-rc.staging-1.staging-42", "1.0.0-rc.staging-1", true},
+		{"v-20.1.0", "", false},
+		{".v-20.1.0.lock", "", false},
+		{".v-.staging-1", "", false},
+	}
+	for _, tt := range tests {
+		version, ok := parseStagingName(tt.name)
+		if version != tt.version || ok != tt.ok {
+			t.Errorf("parseStagingName(%q) = %q, %v, want %q, %v", tt.name, version, ok, tt.version, tt.ok)
+		}
+	}
+}
+
+func TestCleanStaleStaging(t *testing.T) {
+	root := t.TempDir()
+	installPath := filepath.Join(root, "nodejs")
+	stale := filepath.Join(installPath, ".v-20.1.0.staging-1")
+	active := filepath.Join(installPath, ".v-18.0.0.staging-2")
+	installed := filepath.Join(installPath, "v-16.0.0")
+	for _, dir := range []string{stale, active, installed} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lock, err := flock.Acquire(versionLockPath(installPath, "18.0.0"), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Unlock()
+
+	CleanStaleStaging(root)
+
+	if _, err = os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("expected stale staging directory to be removed, got %v", err)
+	}
+	if _, err = os.Stat(active); err != nil {
+		t.Errorf("expected locked staging directory to be kept, got %v", err)
+	}
+	if _, err = os.Stat(installed); err != nil {
+		t.Errorf("expected installed version to be kept, got %v", err)
+	}
+}
+
+func TestInstallComplete(t *testing.T) {
+	installPath := t.TempDir()
+	write := func(version, name string) {
+		dir := filepath.Join(installPath, "v-"+version)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if name != "" {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	write("20.0.0", InstallCompleteMarker)
+	write("18.0.0", "")              // installed before the marker existed
+	write("16.0.0", ReceiptFilename) // interrupted before the marker was written
+
+	b := &impl{Name: "nodejs", InstallPath: installPath}
+	for version, want := range map[Version]bool{"20.0.0": true, "18.0.0": true, "16.0.0": false, "14.0.0": false} {
+		if got := b.CheckRuntimeExist(version); got != want {
+			t.Errorf("CheckRuntimeExist(%s) = %v, want %v", version, got, want)
+		}
+	}
+	if got := b.InstalledList(); len(got) != 2 || got[0] != "20.0.0" || got[1] != "18.0.0" {
+		t.Errorf("InstalledList() = %v, expected the incomplete install to be left out", got)
+	}
+}
+
+func TestCleanInstallPath(t *testing.T) {
+	installPath := t.TempDir()
+	leftover := filepath.Join(installPath, "v-20.1.0")
+	active := filepath.Join(installPath, "v-18.0.0")
+	staging := filepath.Join(installPath, ".v-16.0.0.staging-1")
+	for _, dir := range []string{leftover, active, staging} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lock, err := flock.Acquire(versionLockPath(installPath, "18.0.0"), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Unlock()
+
+	CleanInstallPath(installPath)
+
+	for _, dir := range []string{leftover, staging} {
+		if _, err = os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", dir, err)
+		}
+	}
+	if _, err = os.Stat(active); err != nil {
+		t.Errorf("expected locked package directory to be kept, got %v", err)
+	}
+	for _, version := range []Version{"20.1.0", "18.0.0"} {
+		if _, err = os.Stat(versionLockPath(installPath, version)); err != nil {
+			t.Errorf("expected lock file of %s to be kept, got %v", version, err)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	installPath := t.TempDir()
+	partial := filepath.Join(installPath, "v-20.1.0")
+	if err := os.MkdirAll(partial, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(partial, ReceiptFilename), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b := &impl{Name: "nodejs", InstallPath: installPath}
+
+	if _, err := b.Import("20.1.0", filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("expected importing a missing package to fail")
+	}
+	if _, err := os.Stat(filepath.Join(partial, ReceiptFilename)); err != nil {
+		t.Errorf("expected the partial install to be kept after a failed import, got %v", err)
+	}
+
+	src := filepath.Join(t.TempDir(), "v-20.1.0")
+	if err := os.MkdirAll(filepath.Join(src, "nodejs-20.1.0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, ReceiptFilename), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := b.Import("20.1.0", src)
+	if err != nil || !imported {
+		t.Fatalf("Import() = %v, %v, expected the package to be imported", imported, err)
+	}
+	if !b.CheckRuntimeExist("20.1.0") {
+		t.Error("expected the imported package to be complete")
+	}
+	if _, err = os.Stat(filepath.Join(partial, "nodejs-20.1.0")); err != nil {
+		t.Errorf("expected the partial install to be replaced, got %v", err)
+	}
+	if imported, err = b.Import("20.1.0", src); err != nil || imported {
+		t.Errorf("Import() = %v, %v, expected an installed version to be skipped", imported, err)
+	}
+}
@@ -109,6 +109,52 @@ func NewValue(v any) (Value, error) {
 type Item struct {
 	Val    []byte
 	Expire int64 // Expire time in UnixNano, -1 means never expire
+	// Created is when the item was produced in UnixNano, and Origin describes who produced it.
+	// Both are zero for items written by older versions.
+	Created int64
+	Origin  string
+	// Err is the failure cached by a negative item, which has no value.
+	Err string
+}
+
+// NewItem creates an item produced now, expiring after expireTime.
+func NewItem(value Value, expireTime ExpireTime, origin string) Item {
+	now := time.Now()
+	item := Item{
+		Val:     value,
+		Expire:  int64(NeverExpired),
+		Created: now.UnixNano(),
+		Origin:  origin,
+	}
+	if expireTime != NeverExpired {
+		item.Expire = now.Add(time.Duration(expireTime)).UnixNano()
+	}
+	return item
+}
+
+// Expired reports whether the item is stale.
+func (i Item) Expired() bool {
+	return i.Expire != int64(NeverExpired) && time.Now().UnixNano() > i.Expire
+}
+
+func (i Item) String() string {
+	created := "unknown"
+	if i.Created > 0 {
+		created = time.Unix(0, i.Created).Format(time.RFC3339)
+	}
+	expire := "never"
+	if i.Expire != int64(NeverExpired) {
+		expire = time.Unix(0, i.Expire).Format(time.RFC3339)
+	}
+	origin := i.Origin
+	if origin == "" {
+		origin = "unknown"
+	}
+	state := "value"
+	if i.Err != "" {
+		state = "failure: " + i.Err
+	}
+	return fmt.Sprintf("%s, created at %s by %s, expires at %s", state, created, origin, expire)
 }
 
 // FileCache is a cache that saves to a file
@@ -132,19 +178,22 @@ func NewFileCache(path string) (*FileCache, error) {
 
 // Set a key value pair with a duration
 func (c *FileCache) Set(key string, value Value, expireTime ExpireTime) {
+	c.SetItem(key, NewItem(value, expireTime, ""))
+}
+
+// SetItem stores item under key.
+func (c *FileCache) SetItem(key string, item Item) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if expireTime == NeverExpired {
-		c.items[key] = Item{
-			Val:    value,
-			Expire: int64(NeverExpired),
-		}
-	} else {
-		c.items[key] = Item{
-			Val:    value,
-			Expire: time.Now().Add(time.Duration(expireTime)).UnixNano(),
-		}
-	}
+	c.items[key] = item
+}
+
+// Lookup returns the item of key, even if it expired.
+func (c *FileCache) Lookup(key string) (Item, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, ok := c.items[key]
+	return item, ok
 }
 
 // Get a value by key
@@ -17,7 +17,9 @@
 package cache
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -140,3 +142,119 @@ func TestFileCache(t *testing.T) {
 
 	defer os.Remove("testfile.cache")
 }
+
+func TestEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entry.cache")
+	loads := 0
+	var loadErr error
+	entry := &Entry{
+		Path:     path,
+		Key:      "key",
+		Duration: Duration(time.Hour),
+		Origin:   "test",
+		Load: func() (Value, error) {
+			loads++
+			return Value("fresh"), loadErr
+		},
+	}
+
+	t.Run("TestMissAndHit", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			v, err := entry.Get()
+			if err != nil || string(v) != "fresh" {
+				t.Fatalf("Expected fresh, got %q, %v", v, err)
+			}
+		}
+		if loads != 1 {
+			t.Errorf("Expected 1 load, got %d", loads)
+		}
+		fileCache, _ := NewFileCache(path)
+		item, ok := fileCache.Lookup("key")
+		if !ok || item.Origin != "test" || item.Created == 0 {
+			t.Errorf("Expected item metadata to be recorded, got %+v", item)
+		}
+	})
+
+	t.Run("TestStaleWhileRevalidate", func(t *testing.T) {
+		fileCache, _ := NewFileCache(path)
+		fileCache.SetItem("key", Item{Val: Value("stale"), Expire: time.Now().Add(-time.Minute).UnixNano()})
+		_ = fileCache.Close()
+
+		revalidated := 0
+		entry.Revalidate = func() { revalidated++ }
+		defer func() { entry.Revalidate = nil }()
+		for i := 0; i < 2; i++ {
+			v, err := entry.Get()
+			if err != nil || string(v) != "stale" {
+				t.Fatalf("Expected stale, got %q, %v", v, err)
+			}
+		}
+		if revalidated != 1 {
+			t.Errorf("Expected a single background revalidation, got %d", revalidated)
+		}
+	})
+
+	t.Run("TestRefresh", func(t *testing.T) {
+		loads = 0
+		v, err := entry.Refresh()
+		if err != nil || string(v) != "fresh" || loads != 1 {
+			t.Fatalf("Expected a forced load, got %q, %v, %d loads", v, err, loads)
+		}
+		if _, err = os.Stat(path + revalidatingSuffix); !os.IsNotExist(err) {
+			t.Errorf("Expected the refresh to clear the revalidating marker, got %v", err)
+		}
+	})
+
+	t.Run("TestFailedRevalidationKeepsValue", func(t *testing.T) {
+		fileCache, _ := NewFileCache(path)
+		fileCache.SetItem("key", Item{Val: Value("stale"), Expire: time.Now().Add(-time.Minute).UnixNano()})
+		_ = fileCache.Close()
+
+		loads = 0
+		loadErr = errors.New("network down")
+		defer func() { loadErr = nil }()
+		entry.Revalidate = func() { _, _ = entry.Refresh() }
+		defer func() { entry.Revalidate = nil }()
+		for i := 0; i < 2; i++ {
+			v, err := entry.Get()
+			if err != nil || string(v) != "stale" {
+				t.Fatalf("Expected the stale value to survive a failed revalidation, got %q, %v", v, err)
+			}
+		}
+		if loads != 1 {
+			t.Errorf("Expected the failed revalidation to be retried later, got %d loads", loads)
+		}
+	})
+
+	t.Run("TestNegative", func(t *testing.T) {
+		loads = 0
+		loadErr = errors.New("network down")
+		missing := *entry
+		missing.Key = "missing"
+		if _, err := missing.Refresh(); err == nil || IsCachedError(err) {
+			t.Fatalf("Expected the load error, got %v", err)
+		}
+		_, err := missing.Get()
+		if !IsCachedError(err) {
+			t.Fatalf("Expected a cached failure, got %v", err)
+		}
+		if loads != 1 {
+			t.Errorf("Expected the failure to be cached, got %d loads", loads)
+		}
+		loadErr = nil
+		if v, err := missing.Refresh(); err != nil || string(v) != "fresh" {
+			t.Errorf("Expected refresh to retry, got %q, %v", v, err)
+		}
+	})
+
+	t.Run("TestDisabled", func(t *testing.T) {
+		loads = 0
+		disabled := *entry
+		disabled.Duration = 0
+		_, _ = disabled.Get()
+		_, _ = disabled.Get()
+		if loads != 2 {
+			t.Errorf("Expected every call to load, got %d loads", loads)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/version-fox/vfox/internal/shared/flock"
+	"github.com/version-fox/vfox/internal/shared/logger"
+)
+
+// negativeDuration is how long a failure is cached, at most.
+const negativeDuration = time.Minute
+
+// refreshLockTimeout bounds how long a refresh waits for another process refreshing the same file.
+const refreshLockTimeout = 2 * time.Minute
+
+// revalidatingSuffix names the marker file recording when a background refresh was started.
+const revalidatingSuffix = ".revalidating"
+
+// CachedError is returned when a recent failure is served from the cache.
+type CachedError struct {
+	Msg     string
+	Created time.Time
+}
+
+func (e *CachedError) Error() string {
+	return fmt.Sprintf("%s (cached failure from %s, use --refresh to retry)", e.Msg, e.Created.Format(time.RFC3339))
+}
+
+// Loader produces a fresh value.
+type Loader func() (Value, error)
+
+// Entry is a key of a file cache with a stale-while-revalidate policy:
+// fresh values are served from the cache, stale values are served immediately while
+// Revalidate refreshes them in the background, and failures are cached for a short time.
+type Entry struct {
+	Path     string
+	Key      string
+	Duration Duration // 0 disables the cache, -1 never expires
+	Origin   string   // recorded with every value, see Item
+	Load     Loader
+	// Revalidate starts a refresh of a stale value in the background, see Refresh.
+	Revalidate func()
+}
+
+// Get returns the value of the entry, from the cache if possible.
+func (e *Entry) Get() (Value, error) {
+	if e.Duration == 0 {
+		return e.Load()
+	}
+	fileCache, err := NewFileCache(e.Path)
+	if err != nil {
+		logger.Debugf("Failed to open cache %s: %v\n", e.Path, err)
+		return e.Load()
+	}
+	item, ok := fileCache.Lookup(e.Key)
+	if !ok {
+		logger.Debugf("Cache miss: %s [%s]\n", e.Path, e.Key)
+		return e.Refresh()
+	}
+	logger.Debugf("Cache hit: %s [%s], %s\n", e.Path, e.Key, item)
+	if !item.Expired() {
+		if item.Err != "" {
+			return nil, &CachedError{Msg: item.Err, Created: time.Unix(0, item.Created)}
+		}
+		return item.Val, nil
+	}
+	if item.Err != "" || e.Revalidate == nil {
+		return e.Refresh()
+	}
+	if e.startRevalidate() {
+		logger.Debugf("Serving stale value, revalidating in the background\n")
+		e.Revalidate()
+	} else {
+		logger.Debugf("Serving stale value, a refresh is already running\n")
+	}
+	return item.Val, nil
+}
+
+// Refresh loads a fresh value and stores it. A failure is cached only if there is no previous
+// value, which is otherwise kept and served until the failure is retried. Concurrent refreshes
+// of the same file are serialized; a refresh that waited for another one reuses its result.
+func (e *Entry) Refresh() (Value, error) {
+	started := time.Now()
+	lock, err := flock.Acquire(e.Path+".lock", refreshLockTimeout, nil)
+	if err != nil {
+		logger.Debugf("Failed to lock cache %s: %v\n", e.Path, err)
+		return e.Load()
+	}
+	defer lock.Unlock()
+
+	fileCache, err := NewFileCache(e.Path)
+	if err != nil {
+		logger.Debugf("Failed to open cache %s: %v\n", e.Path, err)
+		return e.Load()
+	}
+	if item, ok := fileCache.Lookup(e.Key); ok && item.Err == "" && item.Created > started.UnixNano() {
+		logger.Debugf("Cache refreshed by another process: %s [%s]\n", e.Path, e.Key)
+		return item.Val, nil
+	}
+
+	value, err := e.Load()
+	if previous, ok := fileCache.Lookup(e.Key); err != nil && ok && previous.Err == "" {
+		// Keep serving the previous value rather than the failure, and retry it after the
+		// negative duration instead of on every read.
+		logger.Debugf("Refresh failed, keeping the previous value: %s [%s]\n", e.Path, e.Key)
+		previous.Expire = time.Now().Add(e.negativeDuration()).UnixNano()
+		fileCache.SetItem(e.Key, previous)
+	} else if err != nil {
+		fileCache.SetItem(e.Key, Item{
+			Expire:  time.Now().Add(e.negativeDuration()).UnixNano(),
+			Created: time.Now().UnixNano(),
+			Origin:  e.Origin,
+			Err:     err.Error(),
+		})
+	} else {
+		fileCache.SetItem(e.Key, NewItem(value, ExpireTime(e.Duration), e.Origin))
+	}
+	if closeErr := fileCache.Close(); closeErr != nil {
+		logger.Debugf("Failed to save cache %s: %v\n", e.Path, closeErr)
+	}
+	_ = os.Remove(e.Path + revalidatingSuffix)
+	return value, err
+}
+
+// startRevalidate reports whether a background refresh should be started, and records
+// that one is. It returns false while another process refreshes the file, or started a
+// background refresh that has not finished within refreshLockTimeout, so that a stale
+// entry read by many shells at once spawns a single refresh.
+func (e *Entry) startRevalidate() bool {
+	lock := flock.New(e.Path + ".lock")
+	if locked, err := lock.TryLock(); err != nil || !locked {
+		return false
+	}
+	defer lock.Unlock()
+
+	marker := e.Path + revalidatingSuffix
+	if info, err := os.Stat(marker); err == nil && time.Since(info.ModTime()) < refreshLockTimeout {
+		return false
+	}
+	if err := os.WriteFile(marker, []byte(time.Now().UTC().Format(time.RFC3339)), 0644); err != nil {
+		logger.Debugf("Failed to write %s: %v\n", marker, err)
+	}
+	return true
+}
+
+func (e *Entry) negativeDuration() time.Duration {
+	if e.Duration > 0 && time.Duration(e.Duration) < negativeDuration {
+		return time.Duration(e.Duration)
+	}
+	return negativeDuration
+}
+
+// IsCachedError reports whether err is a failure served from the cache.
+func IsCachedError(err error) bool {
+	var cachedErr *CachedError
+	return errors.As(err, &cachedErr)
+}
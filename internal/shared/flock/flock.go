@@ -0,0 +1,114 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package flock provides advisory file locks shared between vfox processes.
+package flock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrTimeout is returned by Acquire when the lock is still held after the timeout.
+var ErrTimeout = errors.New("timed out waiting for lock")
+
+// DefaultTimeout is how long vfox waits for another process before giving up,
+// long enough for a slow download to finish.
+const DefaultTimeout = 30 * time.Minute
+
+const pollInterval = 200 * time.Millisecond
+
+// Lock is an exclusive advisory lock on a file. The file is never removed, so that
+// every process always locks the same inode.
+type Lock struct {
+	path string
+	file *os.File
+}
+
+// New returns an unlocked Lock on path.
+func New(path string) *Lock {
+	return &Lock{path: path}
+}
+
+// Path returns the path of the lock file.
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// TryLock acquires the lock without blocking, and reports whether it succeeded.
+func (l *Lock) TryLock() (bool, error) {
+	if l.file != nil {
+		return true, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return false, err
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return false, err
+	}
+	locked, err := tryLock(file)
+	if err != nil || !locked {
+		_ = file.Close()
+		return false, err
+	}
+	l.file = file
+	return true, nil
+}
+
+// Unlock releases the lock. It is a no-op if the lock is not held.
+func (l *Lock) Unlock() error {
+	if l.file == nil {
+		return nil
+	}
+	err := unlock(l.file)
+	closeErr := l.file.Close()
+	l.file = nil
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// Acquire locks path, waiting up to timeout for another process to release it.
+// onWait is called once, before waiting, if the lock is busy.
+func Acquire(path string, timeout time.Duration, onWait func()) (*Lock, error) {
+	lock := New(path)
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	if locked {
+		return lock, nil
+	}
+	if onWait != nil {
+		onWait()
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(pollInterval)
+		if locked, err = lock.TryLock(); err != nil {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			return lock, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %s after %s", ErrTimeout, path, timeout)
+}
@@ -0,0 +1,81 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package flock
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTryLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "test.lock")
+	first := New(path)
+	locked, err := first.TryLock()
+	if err != nil || !locked {
+		t.Fatalf("expected first lock to succeed, got locked=%v err=%v", locked, err)
+	}
+
+	second := New(path)
+	if locked, err = second.TryLock(); err != nil || locked {
+		t.Fatalf("expected second lock to fail, got locked=%v err=%v", locked, err)
+	}
+
+	if err = first.Unlock(); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	if locked, err = second.TryLock(); err != nil || !locked {
+		t.Fatalf("expected lock to succeed after unlock, got locked=%v err=%v", locked, err)
+	}
+	_ = second.Unlock()
+}
+
+func TestAcquireWaits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	holder, err := Acquire(path, time.Second, nil)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+
+	waited := false
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		_ = holder.Unlock()
+	}()
+	lock, err := Acquire(path, 5*time.Second, func() { waited = true })
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	defer lock.Unlock()
+	if !waited {
+		t.Fatal("expected onWait to be called")
+	}
+}
+
+func TestAcquireTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	holder, err := Acquire(path, time.Second, nil)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	defer holder.Unlock()
+
+	if _, err = Acquire(path, 300*time.Millisecond, nil); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+}
@@ -0,0 +1,407 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package httpcache caches the responses to GET requests on disk, keyed by URL. It
+// honours Cache-Control and Expires, and revalidates stale responses with their
+// ETag or Last-Modified, so unchanged resources are not downloaded again.
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/version-fox/vfox/internal/shared/logger"
+)
+
+const (
+	// storedHeader records when a response was stored or last revalidated.
+	storedHeader = "X-Vfox-Stored"
+	// varyPrefix prefixes the request headers named by the Vary header of a stored response.
+	varyPrefix = "X-Vfox-Vary-"
+	// entryFraction limits a response to this fraction of the cache size, so that a
+	// single response cannot evict all others. Downloads of SDK archives bypass the
+	// cache whatever their size, see Bypass.
+	entryFraction = 10
+	fileExt       = ".cache"
+)
+
+// bypassKey marks the context of a request that must not go through the cache.
+type bypassKey struct{}
+
+// Bypass returns a copy of ctx whose requests are sent without looking up or storing
+// responses, for downloads that are written to files and never requested again as is.
+func Bypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// Transport is a http.RoundTripper caching the responses of Base in Dir.
+type Transport struct {
+	Base http.RoundTripper
+	Dir  string
+	// MaxSize is how many bytes the cached responses may take.
+	MaxSize int64
+}
+
+// New creates a transport caching the responses of base, http.DefaultTransport if nil, in dir.
+func New(dir string, maxSize int64, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, Dir: dir, MaxSize: maxSize}
+}
+
+// RoundTrip serves fresh responses from the cache and revalidates stale ones.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !cacheableRequest(req) {
+		return t.Base.RoundTrip(req)
+	}
+	path := t.path(req)
+	cached := t.load(path, req)
+	if cached != nil && fresh(cached.Header, req.Header) {
+		logger.Debugf("HTTP cache hit: %s\n", req.URL)
+		touch(path)
+		return serve(cached), nil
+	}
+
+	outgoing := req
+	if cached != nil {
+		outgoing = conditional(req, cached.Header)
+	}
+	resp, err := t.Base.RoundTrip(outgoing)
+	if err != nil {
+		if cached != nil && req.Context().Err() == nil {
+			logger.Debugf("HTTP cache serving stale %s: %v\n", req.URL, err)
+			return serve(cached), nil
+		}
+		return nil, err
+	}
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		logger.Debugf("HTTP cache revalidated: %s\n", req.URL)
+		resp.Body.Close()
+		for _, name := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified"} {
+			if values, ok := resp.Header[name]; ok {
+				cached.Header[name] = values
+			}
+		}
+		cached.Header.Set(storedHeader, time.Now().UTC().Format(time.RFC3339Nano))
+		t.rewrite(path, cached)
+		return serve(cached), nil
+	}
+	if cached != nil {
+		cached.Body.Close()
+	}
+	if resp.StatusCode != http.StatusOK || !cacheableResponse(resp) {
+		return resp, nil
+	}
+	if limit := t.MaxSize / entryFraction; resp.ContentLength > limit {
+		return resp, nil
+	}
+	resp.Body = &recorder{
+		body:      resp.Body,
+		transport: t,
+		path:      path,
+		head:      storedHead(resp, req),
+		limit:     t.MaxSize / entryFraction,
+	}
+	return resp, nil
+}
+
+// path returns the file of the request, keyed by its URL and credentials.
+func (t *Transport) path(req *http.Request) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.URL.String()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(req.Header.Get("Authorization")))
+	return filepath.Join(t.Dir, hex.EncodeToString(hash.Sum(nil))+fileExt)
+}
+
+// load returns the stored response to req, or nil if there is none.
+func (t *Transport) load(path string, req *http.Request) *http.Response {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		logger.Debugf("HTTP cache entry %s is corrupt: %v\n", path, err)
+		_ = os.Remove(path)
+		return nil
+	}
+	for name, values := range resp.Header {
+		if header, ok := strings.CutPrefix(name, varyPrefix); ok && req.Header.Get(header) != strings.Join(values, ", ") {
+			resp.Body.Close()
+			return nil
+		}
+	}
+	return resp
+}
+
+// rewrite stores the revalidated headers of cached, whose body was read from the cache.
+func (t *Transport) rewrite(path string, cached *http.Response) {
+	body, err := io.ReadAll(cached.Body)
+	if err != nil {
+		return
+	}
+	cached.Body = io.NopCloser(bytes.NewReader(body))
+	head := *cached
+	head.Body = nil
+	t.store(path, &head, body)
+}
+
+// store writes the response head and body to path, replacing it atomically.
+func (t *Transport) store(path string, head *http.Response, body []byte) {
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		logger.Debugf("Failed to create HTTP cache %s: %v\n", t.Dir, err)
+		return
+	}
+	tmp, err := os.CreateTemp(t.Dir, "*.tmp")
+	if err != nil {
+		logger.Debugf("Failed to store HTTP cache entry: %v\n", err)
+		return
+	}
+	resp := *head
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.TransferEncoding = nil
+	resp.Header = head.Header.Clone()
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	err = resp.Write(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		logger.Debugf("Failed to store HTTP cache entry: %v\n", err)
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	t.evict()
+}
+
+// evict removes the least recently used responses until the cache fits in MaxSize.
+func (t *Transport) evict() {
+	entries, err := os.ReadDir(t.Dir)
+	if err != nil {
+		return
+	}
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fileExt {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{filepath.Join(t.Dir, entry.Name()), info.Size(), info.ModTime()})
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= t.MaxSize {
+			break
+		}
+		if err := os.Remove(f.path); err == nil || os.IsNotExist(err) {
+			total -= f.size
+		}
+	}
+}
+
+// serve removes the headers recorded for the cache from a stored response.
+func serve(cached *http.Response) *http.Response {
+	for name := range cached.Header {
+		if name == storedHeader || strings.HasPrefix(name, varyPrefix) {
+			cached.Header.Del(name)
+		}
+	}
+	return cached
+}
+
+// touch marks the response at path as recently used.
+func touch(path string) {
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+// recorder stores the body of a response in the cache once it has been read completely.
+type recorder struct {
+	body      io.ReadCloser
+	transport *Transport
+	path      string
+	head      *http.Response
+	limit     int64
+	buf       bytes.Buffer
+	skipped   bool
+}
+
+func (r *recorder) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if !r.skipped {
+		if int64(r.buf.Len()+n) > r.limit {
+			r.skipped = true
+			r.buf = bytes.Buffer{}
+		} else {
+			r.buf.Write(p[:n])
+		}
+	}
+	if err == io.EOF && !r.skipped {
+		r.skipped = true
+		r.transport.store(r.path, r.head, r.buf.Bytes())
+	}
+	return n, err
+}
+
+func (r *recorder) Close() error {
+	r.skipped = true
+	return r.body.Close()
+}
+
+// storedHead returns the head of resp to store, recording the request headers it varies by.
+func storedHead(resp *http.Response, req *http.Request) *http.Response {
+	head := *resp
+	head.Body = nil
+	head.Header = resp.Header.Clone()
+	head.Header.Set(storedHeader, time.Now().UTC().Format(time.RFC3339Nano))
+	for _, name := range varyHeaders(resp.Header) {
+		if value := req.Header.Get(name); value != "" {
+			head.Header.Set(varyPrefix+name, value)
+		}
+	}
+	return &head
+}
+
+func cacheableRequest(req *http.Request) bool {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return false
+	}
+	if bypass, _ := req.Context().Value(bypassKey{}).(bool); bypass {
+		return false
+	}
+	_, noStore := cacheControl(req.Header)["no-store"]
+	return !noStore
+}
+
+// cacheableResponse reports whether resp may be stored: it must allow storing and be either
+// fresh for a while or revalidatable.
+func cacheableResponse(resp *http.Response) bool {
+	cc := cacheControl(resp.Header)
+	if _, ok := cc["no-store"]; ok {
+		return false
+	}
+	for _, name := range varyHeaders(resp.Header) {
+		if name == "*" {
+			return false
+		}
+	}
+	if resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "" {
+		return true
+	}
+	return lifetime(resp.Header) > 0
+}
+
+// fresh reports whether a response with the stored headers may be served without revalidation.
+func fresh(stored, requested http.Header) bool {
+	if _, ok := cacheControl(requested)["no-cache"]; ok {
+		return false
+	}
+	if _, ok := cacheControl(stored)["no-cache"]; ok {
+		return false
+	}
+	storedAt, err := time.Parse(time.RFC3339Nano, stored.Get(storedHeader))
+	if err != nil {
+		return false
+	}
+	age := time.Since(storedAt)
+	if value, err := strconv.Atoi(stored.Get("Age")); err == nil {
+		age += time.Duration(value) * time.Second
+	}
+	return age < lifetime(stored)
+}
+
+// lifetime returns how long a response stays fresh, from Cache-Control max-age or Expires.
+func lifetime(header http.Header) time.Duration {
+	cc := cacheControl(header)
+	if value, ok := cc["max-age"]; ok {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		return 0
+	}
+	if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		date, err := http.ParseTime(header.Get("Date"))
+		if err != nil {
+			date = time.Now()
+		}
+		return expires.Sub(date)
+	}
+	return 0
+}
+
+// conditional returns a copy of req asking to send the response only if it changed.
+func conditional(req *http.Request, stored http.Header) *http.Request {
+	outgoing := req.Clone(req.Context())
+	if etag := stored.Get("ETag"); etag != "" && outgoing.Header.Get("If-None-Match") == "" {
+		outgoing.Header.Set("If-None-Match", etag)
+	}
+	if modified := stored.Get("Last-Modified"); modified != "" && outgoing.Header.Get("If-Modified-Since") == "" {
+		outgoing.Header.Set("If-Modified-Since", modified)
+	}
+	return outgoing
+}
+
+func cacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, part := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if name != "" {
+				directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+			}
+		}
+	}
+	return directives
+}
+
+func varyHeaders(header http.Header) []string {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
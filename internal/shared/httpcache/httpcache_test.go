@@ -0,0 +1,235 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, client *http.Client, url string, header ...string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestMaxAge(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("index"))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: New(t.TempDir(), 1<<20, nil)}
+
+	for i := 0; i < 3; i++ {
+		resp, body := get(t, client, server.URL)
+		if resp.StatusCode != http.StatusOK || body != "index" {
+			t.Fatalf("unexpected response %d %q", resp.StatusCode, body)
+		}
+		if resp.Header.Get(storedHeader) != "" {
+			t.Errorf("internal header %s served", storedHeader)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+
+	get(t, client, server.URL, "Cache-Control", "no-cache")
+	if requests != 2 {
+		t.Errorf("expected no-cache to revalidate, got %d requests", requests)
+	}
+}
+
+func TestRevalidate(t *testing.T) {
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("index"))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: New(t.TempDir(), 1<<20, nil)}
+
+	for i := 0; i < 3; i++ {
+		resp, body := get(t, client, server.URL)
+		if resp.StatusCode != http.StatusOK || body != "index" {
+			t.Fatalf("unexpected response %d %q", resp.StatusCode, body)
+		}
+	}
+	if requests != 3 || notModified != 2 {
+		t.Errorf("expected 3 requests, 2 revalidated, got %d and %d", requests, notModified)
+	}
+}
+
+func TestNotCached(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store, max-age=60")
+		case "/no-validator":
+		case "/large":
+			w.Header().Set("Cache-Control", "max-age=60")
+			_, _ = w.Write([]byte(strings.Repeat("x", 200)))
+			return
+		case "/error":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: New(t.TempDir(), 1000, nil)}
+
+	for _, path := range []string{"/no-store", "/no-validator", "/large", "/error"} {
+		requests = 0
+		get(t, client, server.URL+path)
+		get(t, client, server.URL+path)
+		if requests != 2 {
+			t.Errorf("%s: expected 2 requests, got %d", path, requests)
+		}
+	}
+}
+
+func TestBypass(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("archive"))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	client := &http.Client{Transport: New(dir, 1000, nil)}
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequestWithContext(Bypass(context.Background()), http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected nothing to be stored, got %d entries", len(entries))
+	}
+}
+
+func TestVary(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept")
+		_, _ = w.Write([]byte(r.Header.Get("Accept")))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: New(t.TempDir(), 1<<20, nil)}
+
+	if _, body := get(t, client, server.URL, "Accept", "application/json"); body != "application/json" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if _, body := get(t, client, server.URL, "Accept", "text/html"); body != "text/html" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("offline")
+}
+
+func TestStaleIfError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = w.Write([]byte("index"))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	get(t, &http.Client{Transport: New(dir, 1<<20, nil)}, server.URL)
+
+	resp, body := get(t, &http.Client{Transport: New(dir, 1<<20, failingTransport{})}, server.URL)
+	if resp.StatusCode != http.StatusOK || body != "index" {
+		t.Errorf("expected stale response, got %d %q", resp.StatusCode, body)
+	}
+}
+
+func TestEvict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	client := &http.Client{Transport: New(dir, 2000, nil)}
+
+	for i := 0; i < 20; i++ {
+		get(t, client, server.URL+"/"+strings.Repeat("a", i))
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total int64
+	for _, entry := range entries {
+		info, _ := entry.Info()
+		total += info.Size()
+	}
+	if total > 2000 || len(entries) == 0 {
+		t.Errorf("expected at most 2000 bytes in the cache, got %d in %d files", total, len(entries))
+	}
+}
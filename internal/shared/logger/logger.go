@@ -16,7 +16,11 @@
 
 package logger
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
 
 type LoggerLevel int
 
@@ -30,19 +34,27 @@ const (
 
 var currentLevel = InfoLevel
 
+// output is stderr, so that logs do not mix with the output of commands, which the shell may evaluate.
+var output io.Writer = os.Stderr
+
 func SetLevel(_level LoggerLevel) {
 	currentLevel = _level
 }
 
+// SetOutput sets where logs are written.
+func SetOutput(w io.Writer) {
+	output = w
+}
+
 func Log(level LoggerLevel, args ...interface{}) {
 	if currentLevel <= level {
-		fmt.Println(args...)
+		fmt.Fprintln(output, args...)
 	}
 }
 
 func Logf(level LoggerLevel, message string, args ...interface{}) {
 	if currentLevel <= level {
-		fmt.Printf(message, args...)
+		fmt.Fprintf(output, message, args...)
 	}
 }
 
@@ -0,0 +1,268 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Range is a set of versions, written as in npm:
+//
+//	>=1.2.3 <2         comparators, all of which must match
+//	1.2.x, 1.2, *      any version with the given components
+//	~1.2.3             patch updates, >=1.2.3 <1.3.0
+//	^1.2.3             updates not changing the leftmost non-zero component, >=1.2.3 <2.0.0
+//	1.2.3 - 2.3        inclusive bounds
+//	^1 || ^2           either range
+//
+// A pre-release only matches if a comparator of the same numbers allows pre-releases,
+// so >=1.2.3-beta matches 1.2.3-rc.1 but not 1.2.4-rc.1.
+type Range struct {
+	sets [][]comparator
+}
+
+type comparator struct {
+	op string
+	v  *Version
+}
+
+// partial is a version of which only the first n components are given, as in 1.2 or 1.x.
+type partial struct {
+	*Version
+	n int
+}
+
+// ParseRange parses a range, see Range.
+func ParseRange(s string) (*Range, error) {
+	r := &Range{}
+	for _, set := range strings.Split(s, "||") {
+		comparators, err := parseSet(set)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q: %w", s, err)
+		}
+		r.sets = append(r.sets, comparators)
+	}
+	return r, nil
+}
+
+// Contains reports whether v is in r. Pre-releases are always allowed if includePrerelease is set.
+func (r *Range) Contains(v *Version, includePrerelease bool) bool {
+	for _, set := range r.sets {
+		if matchSet(set, v, includePrerelease) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSet(set []comparator, v *Version, includePrerelease bool) bool {
+	for _, c := range set {
+		if !c.match(v) {
+			return false
+		}
+	}
+	if !v.IsPrerelease() || includePrerelease {
+		return true
+	}
+	for _, c := range set {
+		if c.v.IsPrerelease() && c.v.compareNumbers(v) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) match(v *Version) bool {
+	cmp := v.Compare(c.v)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+var operators = []string{">=", "<=", "~>", ">", "<", "=", "~", "^"}
+
+func parseSet(set string) ([]comparator, error) {
+	fields := strings.FieldsFunc(set, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' })
+	// Join operators written apart from their version, as in ">= 1.2.3".
+	var tokens []string
+	for i := 0; i < len(fields); i++ {
+		if isOperator(fields[i]) && i+1 < len(fields) {
+			tokens = append(tokens, fields[i]+fields[i+1])
+			i++
+			continue
+		}
+		tokens = append(tokens, fields[i])
+	}
+	if len(tokens) == 3 && tokens[1] == "-" {
+		return hyphenRange(tokens[0], tokens[2])
+	}
+	if len(tokens) == 0 {
+		return []comparator{{op: ">=", v: zero()}}, nil
+	}
+	var comparators []comparator
+	for _, token := range tokens {
+		c, err := parseComparator(token)
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, c...)
+	}
+	return comparators, nil
+}
+
+func isOperator(s string) bool {
+	for _, op := range operators {
+		if s == op {
+			return true
+		}
+	}
+	return false
+}
+
+func parseComparator(token string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range operators {
+		if strings.HasPrefix(token, candidate) {
+			op = candidate
+			break
+		}
+	}
+	p, err := parsePartial(strings.TrimPrefix(token, op))
+	if err != nil {
+		return nil, err
+	}
+	if p.n == 0 {
+		if op == "<" || op == ">" {
+			// nothing is below or above every version
+			return []comparator{{op: "<", v: zero()}}, nil
+		}
+		return []comparator{{op: ">=", v: zero()}}, nil
+	}
+	full := p.n >= 3
+	switch op {
+	case "", "=":
+		if full {
+			return []comparator{{op: "=", v: p.Version}}, nil
+		}
+		return []comparator{{op: ">=", v: p.Version}, {op: "<", v: p.bump(p.n - 1)}}, nil
+	case ">":
+		if full {
+			return []comparator{{op: ">", v: p.Version}}, nil
+		}
+		next := p.bump(p.n - 1)
+		next.Prerelease = nil
+		return []comparator{{op: ">=", v: next}}, nil
+	case ">=":
+		return []comparator{{op: ">=", v: p.Version}}, nil
+	case "<":
+		if full {
+			return []comparator{{op: "<", v: p.Version}}, nil
+		}
+		return []comparator{{op: "<", v: p.floor()}}, nil
+	case "<=":
+		if full {
+			return []comparator{{op: "<=", v: p.Version}}, nil
+		}
+		return []comparator{{op: "<", v: p.bump(p.n - 1)}}, nil
+	case "~", "~>":
+		return []comparator{{op: ">=", v: p.Version}, {op: "<", v: p.bump(min(p.n-1, 1))}}, nil
+	default: // "^"
+		i := 0
+		for i < p.n-1 && p.Number(i) == 0 {
+			i++
+		}
+		return []comparator{{op: ">=", v: p.Version}, {op: "<", v: p.bump(i)}}, nil
+	}
+}
+
+func hyphenRange(from, to string) ([]comparator, error) {
+	lower, err := parsePartial(from)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := parsePartial(to)
+	if err != nil {
+		return nil, err
+	}
+	comparators := []comparator{{op: ">=", v: lower.Version}}
+	switch {
+	case upper.n == 0:
+	case upper.n >= 3:
+		comparators = append(comparators, comparator{op: "<=", v: upper.Version})
+	default:
+		comparators = append(comparators, comparator{op: "<", v: upper.bump(upper.n - 1)})
+	}
+	return comparators, nil
+}
+
+// parsePartial parses a version whose trailing components may be missing or wildcards.
+func parsePartial(s string) (*partial, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "=")
+	if s == "" || s == "*" || s == "x" || s == "X" {
+		return &partial{Version: zero()}, nil
+	}
+	parts := strings.Split(s, ".")
+	for i, part := range parts {
+		if part == "*" || part == "x" || part == "X" {
+			if i == 0 {
+				return &partial{Version: zero()}, nil
+			}
+			v, err := Parse(strings.Join(parts[:i], "."))
+			if err != nil {
+				return nil, err
+			}
+			return &partial{Version: v, n: i}, nil
+		}
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &partial{Version: v, n: len(v.Numbers)}, nil
+}
+
+func zero() *Version {
+	return &Version{Numbers: []uint64{0, 0, 0}}
+}
+
+// bump returns the lowest pre-release of the version following p in its i-th component,
+// so that <bump(i) excludes the pre-releases of that version.
+func (p *partial) bump(i int) *Version {
+	numbers := make([]uint64, max(3, i+1))
+	for j := 0; j < i; j++ {
+		numbers[j] = p.Number(j)
+	}
+	numbers[i] = p.Number(i) + 1
+	return &Version{Numbers: numbers, Prerelease: []string{"0"}}
+}
+
+// floor returns the lowest pre-release of p, so that <floor() excludes every version matching p.
+func (p *partial) floor() *Version {
+	numbers := make([]uint64, max(3, p.n))
+	copy(numbers, p.Numbers[:p.n])
+	return &Version{Numbers: numbers, Prerelease: []string{"0"}}
+}
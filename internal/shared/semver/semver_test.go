@@ -0,0 +1,134 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package semver
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"v1.2", "1.2.0"},
+		{"jdk-21+35", "21.0.0+35"},
+		{"21.0.1.12", "21.0.1.12"},
+		{"go1.22rc1", "1.22.0-rc.1"},
+		{"3.13.0a1", "3.13.0-a.1"},
+		{"1.0.0-beta.2+exp.sha.5114f85", "1.0.0-beta.2+exp.sha.5114f85"},
+		{"release-2.0.0_RC1", "2.0.0-RC.1"},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got := v.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{"", "latest"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("expected Parse(%q) to fail", in)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// each version is older than the next
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2", "go1.22rc1", "go1.22rc10", "go1.22",
+		"1.22.0.1", "2",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := Parse(ordered[i])
+		b, _ := Parse(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+	a, _ := Parse("jdk-21+35")
+	b, _ := Parse("21.0.0+36")
+	if a.Compare(b) != 0 {
+		t.Errorf("expected build metadata to be ignored")
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		rng      string
+		version  string
+		contains bool
+	}{
+		{"*", "1.2.3", true},
+		{"", "1.2.3", true},
+		{"1.2.3", "1.2.3", true},
+		{"1.2", "1.2.9", true},
+		{"1.2", "1.3.0", false},
+		{"1.x", "1.9.9", true},
+		{"1.x", "2.0.0-beta", false},
+		{">=1.2.3 <2", "1.9.0", true},
+		{">= 1.2.3, < 2", "2.0.0", false},
+		{">1.2", "1.2.9", false},
+		{">1.2", "1.3.0", true},
+		{"<=1.2", "1.2.9", true},
+		{"<1.2", "1.1.9", true},
+		{"<1.2", "1.2.0-beta", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"1.2.3 - 2.3", "2.3.9", true},
+		{"1.2.3 - 2.3", "2.4.0", false},
+		{"1.2.3 - 2.3.4", "2.3.4", true},
+		{"^1 || ^3", "3.1.0", true},
+		{"^1 || ^3", "2.1.0", false},
+		{">=1.2.3-beta", "1.2.3-rc.1", true},
+		{">=1.2.3-beta", "1.2.4-rc.1", false},
+		{"^20", "20.1.0-rc.1", false},
+		{"21", "jdk-21+35", true},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.rng)
+		if err != nil {
+			t.Errorf("ParseRange(%q) failed: %v", tt.rng, err)
+			continue
+		}
+		v, err := Parse(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Contains(v, false); got != tt.contains {
+			t.Errorf("%q contains %s = %v, want %v", tt.rng, tt.version, got, tt.contains)
+		}
+	}
+	r, _ := ParseRange("^20")
+	v, _ := Parse("20.1.0-rc.1")
+	if !r.Contains(v, true) {
+		t.Errorf("expected pre-releases to be included")
+	}
+	if _, err := ParseRange(">=latest"); err == nil {
+		t.Errorf("expected an invalid range to fail")
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package semver parses and compares loose semantic versions and ranges of them. It backs
+// the semver Lua module as well as the self-upgrade check.
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed version. Numbers holds every numeric component, so that versions
+// such as 21.0.1.12 keep their fourth component, and missing components compare as 0.
+type Version struct {
+	Numbers    []uint64
+	Prerelease []string
+	Build      string
+	Original   string
+}
+
+// Parse parses a semantic version loosely. A prefix before the first digit is ignored,
+// so v1.2.3, jdk-21+35 and go1.22rc1 are accepted, and a suffix following the numbers
+// directly, as rc1 in go1.22rc1, is a pre-release. Pre-release identifiers are split
+// between letters and digits, so that rc10 is newer than rc9.
+func Parse(s string) (*Version, error) {
+	original := s
+	start := strings.IndexAny(s, "0123456789")
+	if start < 0 {
+		return nil, fmt.Errorf("invalid version %q: no number", original)
+	}
+	s = s[start:]
+
+	v := &Version{Original: original}
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		v.Build = s[i+1:]
+		s = s[:i]
+	}
+	for {
+		end := 0
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+		n, err := strconv.ParseUint(s[:end], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", original, err)
+		}
+		v.Numbers = append(v.Numbers, n)
+		s = s[end:]
+		if len(s) < 2 || s[0] != '.' || s[1] < '0' || s[1] > '9' {
+			break
+		}
+		s = s[1:]
+	}
+	if pre := strings.TrimLeft(s, "-_."); pre != "" {
+		v.Prerelease = splitIdentifiers(pre)
+	} else if s != "" {
+		return nil, fmt.Errorf("invalid version %q: unexpected %q", original, s)
+	}
+	return v, nil
+}
+
+func splitIdentifiers(pre string) []string {
+	var ids []string
+	for _, part := range strings.FieldsFunc(pre, func(r rune) bool { return r == '.' || r == '-' || r == '_' }) {
+		start := 0
+		for i := 1; i < len(part); i++ {
+			if isDigit(part[i]) != isDigit(part[i-1]) {
+				ids = append(ids, part[start:i])
+				start = i
+			}
+		}
+		ids = append(ids, part[start:])
+	}
+	return ids
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Number returns the i-th numeric component, 0 for major, or 0 if the version has none.
+func (v *Version) Number(i int) uint64 {
+	if i < len(v.Numbers) {
+		return v.Numbers[i]
+	}
+	return 0
+}
+
+// IsPrerelease reports whether v is a pre-release.
+func (v *Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// String returns v in canonical form, with at least three numeric components.
+func (v *Version) String() string {
+	var sb strings.Builder
+	for i := 0; i < max(3, len(v.Numbers)); i++ {
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(strconv.FormatUint(v.Number(i), 10))
+	}
+	if v.IsPrerelease() {
+		sb.WriteByte('-')
+		sb.WriteString(strings.Join(v.Prerelease, "."))
+	}
+	if v.Build != "" {
+		sb.WriteByte('+')
+		sb.WriteString(v.Build)
+	}
+	return sb.String()
+}
+
+// Compare returns -1, 0 or 1 if v is older than, the same as, or newer than other.
+// As in semantic versioning, a pre-release is older than its release and build
+// metadata is ignored.
+func (v *Version) Compare(other *Version) int {
+	if c := v.compareNumbers(other); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+func (v *Version) compareNumbers(other *Version) int {
+	for i := 0; i < max(len(v.Numbers), len(other.Numbers)); i++ {
+		a, b := v.Number(i), other.Number(i)
+		if a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+	for i := 0; i < min(len(a), len(b)); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier compares numeric identifiers by value, and before alphanumeric ones.
+func compareIdentifier(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		if na == nb {
+			return 0
+		}
+		if na < nb {
+			return -1
+		}
+		return 1
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
@@ -16,10 +16,17 @@
 
 package shim
 
+import "path/filepath"
+
 // Shim is a struct that contains the binary path and output path which is used to generate the shim.
 type Shim struct {
 	BinaryPath string
 	OutputPath string
+	// Name is the file name of the generated shim, defaults to the base name of BinaryPath.
+	Name string
+	// Args are passed to BinaryPath before the arguments of the caller.
+	// A shim with args is generated as a launcher script instead of a link.
+	Args []string
 }
 
 // NewShim creates a new Shim instance.
@@ -29,3 +36,21 @@ func NewShim(binaryPath, outputPath string) *Shim {
 		OutputPath: outputPath,
 	}
 }
+
+// NewCommandShim creates a shim named name which invokes binaryPath with the given args
+// followed by the arguments of the caller.
+func NewCommandShim(name, binaryPath, outputPath string, args ...string) *Shim {
+	return &Shim{
+		BinaryPath: binaryPath,
+		OutputPath: outputPath,
+		Name:       name,
+		Args:       args,
+	}
+}
+
+func (s *Shim) name() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return filepath.Base(s.BinaryPath)
+}
@@ -19,18 +19,24 @@
 package shim
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/version-fox/vfox/internal/shared/logger"
 	"github.com/version-fox/vfox/internal/shared/util"
 )
 
+const scriptShimContent = `#!/bin/sh
+# Generated by vfox, do not edit.
+exec %s "$@"
+`
+
 // Clear removes the generated shim.
 func (s *Shim) Clear() error {
-	name := filepath.Base(s.BinaryPath)
-	targetShim := filepath.Join(s.OutputPath, name)
-	_, err := os.Readlink(targetShim)
+	targetShim := filepath.Join(s.OutputPath, s.name())
+	_, err := os.Lstat(targetShim)
 	if os.IsNotExist(err) {
 		return nil
 	}
@@ -43,8 +49,19 @@ func (s *Shim) Generate() error {
 		logger.Debugf("Clear shim failed: %s\n", err)
 		return err
 	}
-	name := filepath.Base(s.BinaryPath)
-	targetShim := filepath.Join(s.OutputPath, name)
+	targetShim := filepath.Join(s.OutputPath, s.name())
+	if len(s.Args) > 0 {
+		logger.Debugf("Create script shim %s for %s %v\n", targetShim, s.BinaryPath, s.Args)
+		words := make([]string, 0, len(s.Args)+1)
+		for _, word := range append([]string{s.BinaryPath}, s.Args...) {
+			words = append(words, quote(word))
+		}
+		content := fmt.Sprintf(scriptShimContent, strings.Join(words, " "))
+		if err := os.WriteFile(targetShim, []byte(content), 0755); err != nil {
+			return fmt.Errorf("failed to generate shim: %w", err)
+		}
+		return nil
+	}
 	logger.Debugf("Create shim from %s to %s\n", s.BinaryPath, targetShim)
 	if util.FileExists(targetShim) {
 		_ = os.Remove(targetShim)
@@ -55,3 +72,8 @@ func (s *Shim) Generate() error {
 	}
 	return nil
 }
+
+// quote quotes a word for the POSIX shell.
+func quote(word string) string {
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
@@ -0,0 +1,74 @@
+//go:build !windows
+
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package shim
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateCommandShimPassesArgs(t *testing.T) {
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		t.Skip("echo not available")
+	}
+	outputDir := t.TempDir()
+
+	s := NewCommandShim("greet", echo, outputDir, "it's", "hello")
+	if err := s.Generate(); err != nil {
+		t.Fatalf("failed to generate shim: %v", err)
+	}
+
+	shimPath := filepath.Join(outputDir, "greet")
+	out, err := exec.Command(shimPath, "world").Output()
+	if err != nil {
+		t.Fatalf("failed to run shim: %v", err)
+	}
+	if got, want := string(out), "it's hello world\n"; got != want {
+		t.Fatalf("unexpected shim output: got %q, want %q", got, want)
+	}
+
+	if err := s.Clear(); err != nil {
+		t.Fatalf("failed to clear shim: %v", err)
+	}
+	if _, err := os.Lstat(shimPath); !os.IsNotExist(err) {
+		t.Fatalf("expected shim to be removed, got err: %v", err)
+	}
+}
+
+func TestGenerateShimWithoutArgsCreatesSymlink(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "tool")
+	if err := os.WriteFile(target, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("failed to write target: %v", err)
+	}
+	outputDir := t.TempDir()
+
+	if err := NewShim(target, outputDir).Generate(); err != nil {
+		t.Fatalf("failed to generate shim: %v", err)
+	}
+	link, err := os.Readlink(filepath.Join(outputDir, "tool"))
+	if err != nil {
+		t.Fatalf("expected a symlink: %v", err)
+	}
+	if link != target {
+		t.Fatalf("unexpected link target: got %q, want %q", link, target)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/version-fox/vfox/internal/shared/logger"
 	"github.com/version-fox/vfox/internal/shared/util"
@@ -32,9 +33,12 @@ const shimFileContent = `
 path = "%s"
 `
 
+const shimArgsContent = `args = %s
+`
+
 const cmdShimContent = `
 @echo off
-call "%s" %%*
+call %s %%*
 `
 const ps1ShimContent = `
 param (
@@ -42,7 +46,7 @@ param (
     $params
 )
 
-& '%s' @params
+& %s @params
 `
 
 //go:embed binary/shim.exe
@@ -50,7 +54,7 @@ var shim []byte
 
 // Clear removes the generated shim.
 func (s *Shim) Clear() (err error) {
-	filename := filepath.Base(s.BinaryPath)
+	filename := s.name()
 	ext := filepath.Ext(filename)
 	shimName := filename[:len(filename)-len(ext)] + ".shim"
 	shimBinary := filepath.Join(s.OutputPath, filename)
@@ -75,20 +79,20 @@ func (s *Shim) Generate() error {
 		logger.Debugf("Clear shim failed: %s", err)
 		return err
 	}
-	filename := filepath.Base(s.BinaryPath)
+	filename := s.name()
 	stat, err := os.Stat(s.BinaryPath)
 	if err != nil {
 		return err
 	}
 	targetPath := filepath.Join(s.OutputPath, filename)
 	ext := filepath.Ext(filename)
-	if ext == ".cmd" {
-		if err = os.WriteFile(targetPath, []byte(fmt.Sprintf(cmdShimContent, s.BinaryPath)), stat.Mode()); err != nil {
+	if ext == ".cmd" || ext == ".bat" {
+		if err = os.WriteFile(targetPath, []byte(fmt.Sprintf(cmdShimContent, s.command(`"`))), stat.Mode()); err != nil {
 			return fmt.Errorf("failed to generate shim: %w", err)
 		}
 		return nil
 	} else if ext == ".ps1" {
-		if err = os.WriteFile(targetPath, []byte(fmt.Sprintf(ps1ShimContent, s.BinaryPath)), stat.Mode()); err != nil {
+		if err = os.WriteFile(targetPath, []byte(fmt.Sprintf(ps1ShimContent, s.command("'"))), stat.Mode()); err != nil {
 			return fmt.Errorf("failed to generate shim: %w", err)
 		}
 		return nil
@@ -99,9 +103,22 @@ func (s *Shim) Generate() error {
 	}
 	shimName := filename[:len(filename)-len(ext)] + ".shim"
 	shimFile := filepath.Join(s.OutputPath, shimName)
+	content := fmt.Sprintf(shimFileContent, s.BinaryPath)
+	if len(s.Args) > 0 {
+		content += fmt.Sprintf(shimArgsContent, strings.Join(s.Args, " "))
+	}
 	logger.Debugf("Write shim file to %s", shimFile)
-	if err = os.WriteFile(shimFile, []byte(fmt.Sprintf(shimFileContent, s.BinaryPath)), stat.Mode()); err != nil {
+	if err = os.WriteFile(shimFile, []byte(content), stat.Mode()); err != nil {
 		return fmt.Errorf("failed to generate shim: %w", err)
 	}
 	return nil
 }
+
+// command returns the binary path followed by the args, each wrapped in the given quote.
+func (s *Shim) command(quote string) string {
+	words := make([]string, 0, len(s.Args)+1)
+	for _, word := range append([]string{s.BinaryPath}, s.Args...) {
+		words = append(words, quote+word+quote)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,319 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/bodgit/sevenzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+var ErrEntryNotFound = errors.New("entry not found in archive")
+
+// ArchiveEntry is a file, directory or symlink of an archive.
+type ArchiveEntry struct {
+	// Name is the path of the entry in the archive, with slashes and without a leading "./"
+	// or trailing slash. Unlike Decompress, it keeps the root folder of the archive.
+	Name string
+	Size int64
+	Mode fs.FileMode
+	// Link is the target of a symlink.
+	Link string
+}
+
+// entryName normalizes the name of an entry as stored in an archive, see ArchiveEntry.Name.
+func entryName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = strings.TrimPrefix(name, "./")
+	return strings.TrimSuffix(name, "/")
+}
+
+// entryReader is the content of an entry, closing the archive with it.
+type entryReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *entryReader) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if closeErr := c.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// tarOpener opens the tar stream of an archive, and returns the closer of the archive file.
+type tarOpener func() (*tar.Reader, io.Closer, error)
+
+func openTar(src string, decompress func(r io.Reader) (io.Reader, error)) tarOpener {
+	return func() (*tar.Reader, io.Closer, error) {
+		file, err := os.Open(src)
+		if err != nil {
+			return nil, nil, err
+		}
+		r, err := decompress(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, nil, err
+		}
+		closer := &entryReader{closers: []io.Closer{file}}
+		if c, ok := r.(io.Closer); ok {
+			closer.closers = []io.Closer{c, file}
+		}
+		return tar.NewReader(r), closer, nil
+	}
+}
+
+func listTar(open tarOpener) ([]ArchiveEntry, error) {
+	tr, closer, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	var entries []ArchiveEntry
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if name := entryName(header.Name); name != "" {
+			entries = append(entries, ArchiveEntry{
+				Name: name,
+				Size: header.Size,
+				Mode: header.FileInfo().Mode(),
+				Link: header.Linkname,
+			})
+		}
+	}
+}
+
+func openTarEntry(open tarOpener, name string) (io.ReadCloser, error) {
+	tr, closer, err := open()
+	if err != nil {
+		return nil, err
+	}
+	name = entryName(name)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = closer.Close()
+			return nil, err
+		}
+		if entryName(header.Name) != name {
+			continue
+		}
+		if !header.FileInfo().Mode().IsRegular() {
+			_ = closer.Close()
+			return nil, fmt.Errorf("%s is not a regular file", name)
+		}
+		return &entryReader{Reader: tr, closers: []io.Closer{closer}}, nil
+	}
+	_ = closer.Close()
+	return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, name)
+}
+
+func (g *GzipTarDecompressor) tar() tarOpener {
+	return openTar(g.src, func(r io.Reader) (io.Reader, error) {
+		return gzip.NewReader(r)
+	})
+}
+
+// List returns the entries of the archive.
+func (g *GzipTarDecompressor) List() ([]ArchiveEntry, error) {
+	return listTar(g.tar())
+}
+
+// Open returns the content of the regular file name of the archive.
+func (g *GzipTarDecompressor) Open(name string) (io.ReadCloser, error) {
+	return openTarEntry(g.tar(), name)
+}
+
+func (g *XZTarDecompressor) tar() tarOpener {
+	return openTar(g.src, func(r io.Reader) (io.Reader, error) {
+		return xz.NewReader(bufio.NewReader(r))
+	})
+}
+
+// List returns the entries of the archive.
+func (g *XZTarDecompressor) List() ([]ArchiveEntry, error) {
+	return listTar(g.tar())
+}
+
+// Open returns the content of the regular file name of the archive.
+func (g *XZTarDecompressor) Open(name string) (io.ReadCloser, error) {
+	return openTarEntry(g.tar(), name)
+}
+
+func (b *Bzip2TarDecompressor) tar() tarOpener {
+	return openTar(b.src, func(r io.Reader) (io.Reader, error) {
+		return bzip2.NewReader(r), nil
+	})
+}
+
+// List returns the entries of the archive.
+func (b *Bzip2TarDecompressor) List() ([]ArchiveEntry, error) {
+	return listTar(b.tar())
+}
+
+// Open returns the content of the regular file name of the archive.
+func (b *Bzip2TarDecompressor) Open(name string) (io.ReadCloser, error) {
+	return openTarEntry(b.tar(), name)
+}
+
+func (z *ZstdTarDecompressor) tar() tarOpener {
+	return openTar(z.src, func(r io.Reader) (io.Reader, error) {
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	})
+}
+
+// List returns the entries of the archive.
+func (z *ZstdTarDecompressor) List() ([]ArchiveEntry, error) {
+	return listTar(z.tar())
+}
+
+// Open returns the content of the regular file name of the archive.
+func (z *ZstdTarDecompressor) Open(name string) (io.ReadCloser, error) {
+	return openTarEntry(z.tar(), name)
+}
+
+// List returns the entries of the archive.
+func (z *ZipDecompressor) List() ([]ArchiveEntry, error) {
+	r, err := zip.OpenReader(z.src)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var entries []ArchiveEntry
+	for _, f := range r.File {
+		entry, err := archiveEntry(f.Name, f.FileInfo(), f.Open)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Name != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// Open returns the content of the regular file name of the archive.
+func (z *ZipDecompressor) Open(name string) (io.ReadCloser, error) {
+	r, err := zip.OpenReader(z.src)
+	if err != nil {
+		return nil, err
+	}
+	name = entryName(name)
+	for _, f := range r.File {
+		if entryName(f.Name) == name {
+			return openEntry(name, f.FileInfo(), f.Open, r)
+		}
+	}
+	_ = r.Close()
+	return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, name)
+}
+
+// List returns the entries of the archive.
+func (s *SevenZipDecompressor) List() ([]ArchiveEntry, error) {
+	r, err := sevenzip.OpenReader(s.src)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var entries []ArchiveEntry
+	for _, f := range r.File {
+		entry, err := archiveEntry(f.Name, f.FileInfo(), f.Open)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Name != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// Open returns the content of the regular file name of the archive.
+func (s *SevenZipDecompressor) Open(name string) (io.ReadCloser, error) {
+	r, err := sevenzip.OpenReader(s.src)
+	if err != nil {
+		return nil, err
+	}
+	name = entryName(name)
+	for _, f := range r.File {
+		if entryName(f.Name) == name {
+			return openEntry(name, f.FileInfo(), f.Open, r)
+		}
+	}
+	_ = r.Close()
+	return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, name)
+}
+
+// archiveEntry describes an entry of a zip or 7z archive, whose symlinks store their target as content.
+func archiveEntry(name string, info fs.FileInfo, open func() (io.ReadCloser, error)) (ArchiveEntry, error) {
+	entry := ArchiveEntry{Name: entryName(name), Size: info.Size(), Mode: info.Mode()}
+	if isSymlink(info) {
+		rc, err := open()
+		if err != nil {
+			return entry, err
+		}
+		defer rc.Close()
+		target, err := io.ReadAll(rc)
+		if err != nil {
+			return entry, fmt.Errorf("%s: reading symlink target: %w", name, err)
+		}
+		entry.Link = strings.TrimSpace(string(target))
+	}
+	return entry, nil
+}
+
+// openEntry opens an entry of a zip or 7z archive, closing archive with it.
+func openEntry(name string, info fs.FileInfo, open func() (io.ReadCloser, error), archive io.Closer) (io.ReadCloser, error) {
+	if !info.Mode().IsRegular() {
+		_ = archive.Close()
+		return nil, fmt.Errorf("%s is not a regular file", name)
+	}
+	rc, err := open()
+	if err != nil {
+		_ = archive.Close()
+		return nil, err
+	}
+	return &entryReader{Reader: rc, closers: []io.Closer{rc, archive}}, nil
+}
@@ -0,0 +1,291 @@
+/*
+ *    Copyright 2026 Han Li and contributors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Compressor writes an archive, in a format read by the Decompressor of its file extension.
+type Compressor interface {
+	// AddFile adds a regular file named name with the given content.
+	AddFile(name string, content []byte, mode fs.FileMode) error
+	// AddDir adds the content of the directory src under prefix, keeping file modes and symlinks.
+	AddDir(src, prefix string) error
+	// Close flushes the archive and closes the underlying file.
+	Close() error
+}
+
+// NewCompressor creates the archive dest in format, tar.gz, tar.xz, tar.zst or zip, or in the format
+// of the file extension of dest if format is empty. It truncates dest if it exists.
+func NewCompressor(dest, format string) (Compressor, error) {
+	if format == "" {
+		format = filepath.Base(dest)
+	}
+	switch {
+	case strings.HasSuffix(format, "tar.gz") || strings.HasSuffix(format, "tgz"):
+		return compressor(NewGzipTarCompressor(dest))
+	case strings.HasSuffix(format, "tar.xz"):
+		return compressor(NewXZTarCompressor(dest))
+	case strings.HasSuffix(format, "tar.zst") || strings.HasSuffix(format, "tzst"):
+		return compressor(NewZstdTarCompressor(dest))
+	case strings.HasSuffix(format, "zip"):
+		return compressor(NewZipCompressor(dest))
+	}
+	return nil, fmt.Errorf("unsupported archive format %s", format)
+}
+
+// compressor returns c as a Compressor, or a nil Compressor on error.
+func compressor[T Compressor](c T, err error) (Compressor, error) {
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// TarCompressor writes a compressed tar archive.
+type TarCompressor struct {
+	file *os.File
+	cw   io.WriteCloser
+	tw   *tar.Writer
+}
+
+// NewZstdTarCompressor creates the zstd compressed tar archive dest, truncating it if it exists.
+func NewZstdTarCompressor(dest string) (*TarCompressor, error) {
+	return newTarCompressor(dest, func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w)
+	})
+}
+
+// NewGzipTarCompressor creates the gzip compressed tar archive dest, truncating it if it exists.
+func NewGzipTarCompressor(dest string) (*TarCompressor, error) {
+	return newTarCompressor(dest, func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	})
+}
+
+// NewXZTarCompressor creates the xz compressed tar archive dest, truncating it if it exists.
+func NewXZTarCompressor(dest string) (*TarCompressor, error) {
+	return newTarCompressor(dest, func(w io.Writer) (io.WriteCloser, error) {
+		return xz.NewWriter(w)
+	})
+}
+
+func newTarCompressor(dest string, compress func(w io.Writer) (io.WriteCloser, error)) (*TarCompressor, error) {
+	file, err := os.Create(dest)
+	if err != nil {
+		return nil, err
+	}
+	cw, err := compress(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &TarCompressor{
+		file: file,
+		cw:   cw,
+		tw:   tar.NewWriter(cw),
+	}, nil
+}
+
+// AddFile adds a regular file named name with the given content.
+func (z *TarCompressor) AddFile(name string, content []byte, mode fs.FileMode) error {
+	header := &tar.Header{
+		Name:    filepath.ToSlash(name),
+		Mode:    int64(mode.Perm()),
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}
+	if err := z.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := z.tw.Write(content)
+	return err
+}
+
+// AddDir adds the content of the directory src under prefix, keeping file modes and symlinks.
+func (z *TarCompressor) AddDir(src, prefix string) error {
+	prefix = filepath.ToSlash(prefix)
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(prefix, filepath.ToSlash(rel))
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err = z.tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return z.copyFile(p)
+	})
+}
+
+func (z *TarCompressor) copyFile(p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err = io.Copy(z.tw, f); err != nil {
+		return fmt.Errorf("failed to add %s: %w", p, err)
+	}
+	return nil
+}
+
+// Close flushes the archive and closes the underlying file.
+func (z *TarCompressor) Close() error {
+	twErr := z.tw.Close()
+	cwErr := z.cw.Close()
+	fileErr := z.file.Close()
+	if twErr != nil {
+		return twErr
+	}
+	if cwErr != nil {
+		return cwErr
+	}
+	return fileErr
+}
+
+// ZipCompressor writes a zip archive.
+type ZipCompressor struct {
+	file *os.File
+	zw   *zip.Writer
+}
+
+// NewZipCompressor creates the zip archive dest, truncating it if it exists.
+func NewZipCompressor(dest string) (*ZipCompressor, error) {
+	file, err := os.Create(dest)
+	if err != nil {
+		return nil, err
+	}
+	return &ZipCompressor{file: file, zw: zip.NewWriter(file)}, nil
+}
+
+// AddFile adds a regular file named name with the given content.
+func (z *ZipCompressor) AddFile(name string, content []byte, mode fs.FileMode) error {
+	header := &zip.FileHeader{
+		Name:     filepath.ToSlash(name),
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	}
+	header.SetMode(mode.Perm())
+	w, err := z.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// AddDir adds the content of the directory src under prefix, keeping file modes and symlinks,
+// which are stored with their target as content.
+func (z *ZipCompressor) AddDir(src, prefix string) error {
+	prefix = filepath.ToSlash(prefix)
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(prefix, filepath.ToSlash(rel))
+		if info.IsDir() {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+		w, err := z.zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(w, link)
+			return err
+		case info.Mode().IsRegular():
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if _, err = io.Copy(w, f); err != nil {
+				return fmt.Errorf("failed to add %s: %w", p, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Close flushes the archive and closes the underlying file.
+func (z *ZipCompressor) Close() error {
+	zwErr := z.zw.Close()
+	fileErr := z.file.Close()
+	if zwErr != nil {
+		return zwErr
+	}
+	return fileErr
+}
@@ -36,6 +36,10 @@ import (
 
 type Decompressor interface {
 	Decompress(dest string) error
+	// List returns the entries of the archive.
+	List() ([]ArchiveEntry, error)
+	// Open returns the content of the regular file name of the archive, see ArchiveEntry.Name.
+	Open(name string) (io.ReadCloser, error)
 }
 
 type symlink struct {
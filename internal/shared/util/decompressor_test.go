@@ -18,6 +18,8 @@ package util
 
 import (
 	"archive/tar"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -420,3 +422,68 @@ func writeXzTar(t *testing.T, archivePath string, name string, body string) {
 //		}
 //	}
 //}
+
+func TestXZTarDecompressorListAndOpen(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "test.tar.xz")
+	body := "Hello from root!"
+
+	writeXzTar(t, archivePath, "root/test.txt", body)
+
+	decompressor := NewDecompressor(archivePath)
+	entries, err := decompressor.List()
+	if err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "root/test.txt" || entries[0].Size != int64(len(body)) {
+		t.Fatalf("Unexpected entries %+v", entries)
+	}
+
+	rc, err := decompressor.Open("root/test.txt")
+	if err != nil {
+		t.Fatalf("Failed to open: %v", err)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != body {
+		t.Errorf("Expected %q, got %q", body, string(content))
+	}
+
+	if _, err := decompressor.Open("root/missing.txt"); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("Expected ErrEntryNotFound, got %v", err)
+	}
+}
+
+func TestNewCompressor(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, name := range []string{"test.tar.gz", "test.tgz", "test.tar.xz", "test.tar.zst", "test.zip"} {
+		archivePath := filepath.Join(tempDir, name)
+		compressor, err := NewCompressor(archivePath, "")
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		if err := compressor.AddFile("root/test.txt", []byte("Hello!"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := compressor.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		rc, err := NewDecompressor(archivePath).Open("root/test.txt")
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", name, err)
+		}
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil || string(content) != "Hello!" {
+			t.Errorf("Expected %q in %s, got %q (%v)", "Hello!", name, string(content), err)
+		}
+	}
+
+	if _, err := NewCompressor(filepath.Join(tempDir, "test.rar"), ""); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
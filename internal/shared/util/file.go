@@ -18,6 +18,7 @@ package util
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
@@ -88,6 +89,18 @@ func MovePath(src, dst string) error {
 	return os.RemoveAll(src)
 }
 
+// CopyPath copies a file or directory to the target path, keeping file modes.
+func CopyPath(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return copyDir(src, dst)
+	}
+	return copySingleFile(src, dst, info.Mode())
+}
+
 // MoveFiles Move a folder or file to a specified directory
 func MoveFiles(src, targetDir string) error {
 	info, err := os.Stat(src)
@@ -221,3 +234,37 @@ func copySingleFile(src, dst string, mode os.FileMode) error {
 	}
 	return ChangeModeIfNot(dst, mode)
 }
+
+// DirSize returns the total size of the regular files under path. Symlinks are not followed.
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// FormatSize formats a size in bytes for humans, e.g. 1.5 MiB.
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
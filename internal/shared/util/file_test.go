@@ -104,3 +104,38 @@ func TestMovePathReturnsNonCrossDeviceErrors(t *testing.T) {
 		t.Fatalf("expected destination file to be absent, got %v", statErr)
 	}
 }
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "nested"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "nested", "b"), make([]byte, 24), 0644); err != nil {
+		t.Fatal(err)
+	}
+	size, err := DirSize(dir)
+	if err != nil {
+		t.Fatalf("DirSize() failed: %v", err)
+	}
+	if size != 124 {
+		t.Fatalf("DirSize() = %d, want 124", size)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+		3 << 30:         "3.0 GiB",
+	}
+	for size, want := range tests {
+		if got := FormatSize(size); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", size, got, want)
+		}
+	}
+}